@@ -0,0 +1,123 @@
+package rtf2txt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/EndFirstCorp/peekingReader"
+)
+
+// Analysis is a security report of an RTF document. It is produced by
+// parsing the document only. Nothing embedded in it is opened or run
+type Analysis struct {
+	Header   string // first bytes of the document, which should be {\rtf1
+	MaxDepth int    // deepest nesting of groups
+	Objects  []ObjectReport
+	Findings []Finding
+}
+
+// ObjectReport describes an OLE object found by Analyze
+type ObjectReport struct {
+	Offset    int64 // offset of the \object control word
+	Class     string
+	Size      int  // bytes of decoded \objdata
+	Linked    bool // linked rather than embedded
+	Update    bool // \objupdate
+	AutoLink  bool // \objautlink
+	Anomalies []string
+}
+
+// Finding is something unusual found by Analyze at an offset of the RTF data
+type Finding struct {
+	Offset  int64
+	Message string
+}
+
+// maxControlLength is the longest control word allowed by the RTF spec
+const maxControlLength = 32
+
+// Analyze is used to check an io.Reader containing RTF data for signs of
+// malicious content. The analysis is returned even when the document can't
+// be fully parsed, in which case the error is also returned
+func Analyze(r io.Reader) (*Analysis, error) {
+	pr := peekingReader.NewBufReader(r)
+	a := &Analysis{}
+	header, _ := pr.Peek(len(`{\rtf1`))
+	a.Header = string(header)
+	if a.Header != `{\rtf1` {
+		a.Findings = append(a.Findings, Finding{0, fmt.Sprintf("document starts with %q instead of {\\rtf1", a.Header)})
+	}
+
+	c := newConverter(pr, Options{IncludeHidden: true})
+	c.analysis = a
+	err := c.convert()
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) { // the finding already has the offset
+		a.Findings = append(a.Findings, Finding{parseErr.Offset, "document can't be parsed: " + parseErr.Err.Error()})
+	}
+	a.MaxDepth = c.maxDepth
+	for _, o := range c.objects {
+		a.Objects = append(a.Objects, o.report())
+	}
+	return a, err
+}
+
+// analyzeControl checks a control word which started at offset for padding,
+// length and delimiters which are used to hide control words from scanners
+func (c *converter) analyzeControl(offset int64, control string, num int) {
+	if c.analysis == nil || control == "*" || strings.HasPrefix(control, "'") {
+		return
+	}
+	size := int(c.offset() - offset - 1) // without the backslash
+	name := control
+	if strings.HasSuffix(control, "N") {
+		name = control[:len(control)-1]
+		if digits := len(strconv.Itoa(num)); size > len(name)+digits {
+			c.finding(offset, fmt.Sprintf("\\%s has a padded parameter", control))
+		}
+	} else if strings.IndexAny(control, "0123456789") >= 0 {
+		c.finding(offset, fmt.Sprintf("\\%s has a parameter out of range", control))
+	}
+	if len(name) > maxControlLength {
+		c.finding(offset, fmt.Sprintf("\\%s is longer than %d characters", name, maxControlLength))
+	}
+	if p, err := c.r.Peek(1); err == nil && strings.IndexByte("\t\f\v\x00", p[0]) >= 0 {
+		c.finding(offset, fmt.Sprintf("\\%s is followed by unusual whitespace 0x%02x", control, p[0]))
+	}
+}
+
+func (c *converter) finding(offset int64, message string) {
+	if c.analysis != nil {
+		c.analysis.Findings = append(c.analysis.Findings, Finding{offset, message})
+	}
+}
+
+// report describes the object and anything unusual about it
+func (o *Object) report() ObjectReport {
+	r := ObjectReport{Offset: o.offset, Class: o.Class, Size: len(o.Data), Linked: o.Linked, Update: o.Update, AutoLink: o.AutoLink}
+	if strings.HasPrefix(strings.ToLower(o.Class), "equation") || strings.HasPrefix(strings.ToLower(o.headerClass), "equation") {
+		r.Anomalies = append(r.Anomalies, "Equation Editor object, which is commonly exploited")
+	}
+	if o.Data == nil {
+		return r
+	}
+	if o.err != nil {
+		r.Anomalies = append(r.Anomalies, "invalid OLE1 data: "+o.err.Error())
+	}
+	if o.version != 0x0501 {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf("unexpected OLE1 version 0x%08x", o.version))
+	}
+	if o.headerClass != "" && !strings.EqualFold(o.headerClass, o.Class) {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf("\\objclass %s doesn't match OLE1 class %s", o.Class, o.headerClass))
+	}
+	if o.trailing > 0 {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf("%d bytes follow the OLE1 data", o.trailing))
+	}
+	if o.junk > 0 {
+		r.Anomalies = append(r.Anomalies, fmt.Sprintf("%d characters in \\objdata aren't hex digits", o.junk))
+	}
+	return r
+}
@@ -0,0 +1,57 @@
+package rtf2txt
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	equation := hex.EncodeToString(append(ole1("Equation.3", []byte{1, 2}), 0x90, 0x90))
+	doc := `{\rtf1{\object\objemb\objupdate{\*\objclass Word.Document.8}{\*\objdata ` + equation[:20] + " \t " + equation[20:] + `}}` +
+		`{\f00000463\fs24	hi}{{{\b x}}}{\abcdefghijklmnopqrstuvwxyzabcdefgh1 }\bin99999999999999999999 \bin5 ab}`
+	a, err := Analyze(strings.NewReader(doc))
+	if err == nil || a == nil {
+		t.Fatal("expected analysis with error", err, a)
+	}
+	if a.Header != `{\rtf1` || a.MaxDepth != 4 {
+		t.Error("expected header and depth", a.Header, a.MaxDepth)
+	}
+	if len(a.Objects) != 1 {
+		t.Fatal("expected object", a.Objects)
+	}
+	o := a.Objects[0]
+	if o.Offset != 7 || o.Class != "Word.Document.8" || !o.Update || o.AutoLink || o.Size != len(equation)/2 {
+		t.Error("expected object report", o)
+	}
+	expected := []string{
+		"Equation Editor object, which is commonly exploited",
+		"\\objclass Word.Document.8 doesn't match OLE1 class Equation.3",
+		"2 bytes follow the OLE1 data",
+		"3 characters in \\objdata aren't hex digits",
+	}
+	if strings.Join(o.Anomalies, "|") != strings.Join(expected, "|") {
+		t.Error("expected anomalies", o.Anomalies)
+	}
+
+	var messages []string
+	for _, f := range a.Findings {
+		messages = append(messages, f.Message)
+	}
+	expected = []string{
+		"\\fN has a padded parameter",
+		"\\fsN is followed by unusual whitespace 0x09",
+		"\\abcdefghijklmnopqrstuvwxyzabcdefgh is longer than 32 characters",
+		"\\bin99999999999999999999 has a parameter out of range",
+		"\\bin length 5 is invalid or exceeds the remaining data",
+		"document can't be parsed: EOF",
+	}
+	if strings.Join(messages, "|") != strings.Join(expected, "|") {
+		t.Error("expected findings", messages)
+	}
+
+	a, err = Analyze(strings.NewReader(`{\rtvpn\ansi hi}`))
+	if err != nil || len(a.Findings) != 1 || a.Findings[0].Message != `document starts with "{\\rtvp" instead of {\rtf1` {
+		t.Error("expected header finding", err, a.Findings)
+	}
+}
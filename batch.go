@@ -0,0 +1,194 @@
+package rtf2txt
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BatchOptions control how ConvertFS converts a tree of RTF files
+type BatchOptions struct {
+	Options     // used to convert each file. Calls of Diagnostics are serialized and set Path
+	Workers int // files converted at once, runtime.NumCPU() when 0
+}
+
+// BatchSummary is the outcome of converting a tree of RTF files
+type BatchSummary struct {
+	Converted int          // files written
+	Skipped   int          // files whose text was already up to date
+	Failed    []BatchError // sorted by path
+}
+
+// BatchError is the error for one file of a batch
+type BatchError struct {
+	Path string // slash separated path of the RTF file
+	Err  error
+}
+
+func (e BatchError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ConvertDir is like ConvertFS for the files in the directory dir
+func ConvertDir(ctx context.Context, dir, outDir string, opts BatchOptions) (*BatchSummary, error) {
+	return ConvertFS(ctx, os.DirFS(dir), outDir, opts)
+}
+
+// ConvertFS converts every .rtf file in fsys to a .txt file with the same
+// path under outDir. Files whose text is at least as new as the RTF file are
+// skipped. Files which can't be converted are listed in the summary, as are
+// files whose text would overwrite the text of another, such as x.RTF next
+// to x.rtf. An error is only returned when fsys can't be read or ctx is
+// done, in which case the summary so far is also returned
+func ConvertFS(ctx context.Context, fsys fs.FS, outDir string, opts BatchOptions) (*BatchSummary, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if diagnostics := opts.Diagnostics; diagnostics != nil { // files are converted at once
+		var mu sync.Mutex
+		opts.Diagnostics = func(d Diagnostic) {
+			mu.Lock()
+			defer mu.Unlock()
+			diagnostics(d)
+		}
+	}
+	summary := &BatchSummary{}
+	var mu sync.Mutex
+	record := func(name string, skipped bool, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			summary.Failed = append(summary.Failed, BatchError{name, err})
+		case skipped:
+			summary.Skipped++
+		default:
+			summary.Converted++
+		}
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				fileOpts := opts.Options
+				if diagnostics := opts.Diagnostics; diagnostics != nil {
+					fileOpts.Diagnostics = func(d Diagnostic) {
+						d.Path = name
+						diagnostics(d)
+					}
+				}
+				skipped, err := convertBatchFile(ctx, fsys, name, outDir, fileOpts)
+				if ctx.Err() == nil {
+					record(name, skipped, err)
+				}
+			}
+		}()
+	}
+
+	texts := make(map[string]string) // RTF file of each text file
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if name == "." {
+				return err
+			}
+			record(name, false, err) // a directory which can't be read is skipped
+			return nil
+		}
+		if d.IsDir() || !strings.EqualFold(path.Ext(name), ".rtf") {
+			return nil
+		}
+		if other, found := texts[textName(name)]; found {
+			record(name, false, fmt.Errorf("Text file %s is already written for %s", textName(name), other))
+			return nil
+		}
+		texts[textName(name)] = name
+		select {
+		case jobs <- name:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(jobs)
+	wg.Wait()
+	sort.Slice(summary.Failed, func(i, j int) bool { return summary.Failed[i].Path < summary.Failed[j].Path })
+	if err == nil {
+		err = ctx.Err()
+	}
+	return summary, err
+}
+
+// convertBatchFile writes the text of the RTF file name in fsys under
+// outDir. Nothing is written when the conversion fails
+func convertBatchFile(ctx context.Context, fsys fs.FS, name, outDir string, opts Options) (bool, error) {
+	out := filepath.Join(outDir, filepath.FromSlash(textName(name)))
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return false, err
+	}
+	if o, err := os.Stat(out); err == nil && !o.ModTime().Before(info.ModTime()) {
+		return true, nil
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	text, err := TextContext(ctx, f, opts)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return false, err
+	}
+	if err := writeFileAtomic(out, text.Bytes()); err != nil {
+		return false, fmt.Errorf("can't write text: %w", err)
+	}
+	return false, nil
+}
+
+// textName returns the slash separated path of the text file of an RTF file
+func textName(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + ".txt"
+}
+
+// writeFileAtomic writes data to a temporary file which is renamed to name,
+// so that a batch which stops while writing doesn't leave a partial file
+// which looks up to date
+func writeFileAtomic(name string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
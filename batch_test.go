@@ -0,0 +1,102 @@
+package rtf2txt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestConvertFS(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	fsys := fstest.MapFS{
+		"a.rtf":         {Data: []byte(`{\rtf1\pard\f0 one\par}`), ModTime: old},
+		"sub/b.RTF":     {Data: []byte(`{\rtf1\pard\f0 two\par}`), ModTime: old},
+		"sub/bad.rtf":   {Data: []byte(`{\rtf1\pard\f0 hello\f463 hi`), ModTime: old},
+		"sub/notes.txt": {Data: []byte("not rtf"), ModTime: old},
+	}
+	out := t.TempDir()
+	summary, err := ConvertFS(context.Background(), fsys, out, BatchOptions{Workers: 2})
+	if err != nil || summary.Converted != 2 || summary.Skipped != 0 || len(summary.Failed) != 1 {
+		t.Fatal("expected converted files", err, summary)
+	}
+	if f := summary.Failed[0]; f.Path != "sub/bad.rtf" || !errors.Is(f, ErrTruncated) {
+		t.Error("expected failed file", f)
+	}
+	if data, err := os.ReadFile(filepath.Join(out, "sub", "b.txt")); err != nil || string(data) != "two " {
+		t.Error("expected mirrored output", err, string(data))
+	}
+	if _, err := os.Stat(filepath.Join(out, "sub", "bad.txt")); err == nil {
+		t.Error("expected no output for failed file")
+	}
+
+	fsys["a.rtf"].ModTime = time.Now().Add(time.Hour)
+	fsys["a.rtf"].Data = []byte(`{\rtf1\pard\f0 changed\par}`)
+	summary, err = ConvertFS(context.Background(), fsys, out, BatchOptions{Options: Options{ParagraphBreak: "\n"}})
+	if err != nil || summary.Converted != 1 || summary.Skipped != 1 || len(summary.Failed) != 1 {
+		t.Error("expected up to date file to be skipped", err, summary)
+	}
+	if data, err := os.ReadFile(filepath.Join(out, "a.txt")); err != nil || string(data) != "changed\n" {
+		t.Error("expected changed file to be converted", err, string(data))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ConvertFS(ctx, fsys, t.TempDir(), BatchOptions{}); err != context.Canceled {
+		t.Error("expected canceled batch", err)
+	}
+	if _, err := ConvertDir(context.Background(), filepath.Join(out, "missing"), t.TempDir(), BatchOptions{}); err == nil {
+		t.Error("expected missing directory error")
+	}
+}
+
+func TestConvertFSDiagnostics(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for i := 0; i < 20; i++ {
+		fsys[fmt.Sprintf("%d.rtf", i)] = &fstest.MapFile{Data: []byte(`{\rtf1\unknownword\pard\f0 text\par}`)}
+	}
+	paths := make(map[string]int) // not synchronized, as the calls are
+	opts := BatchOptions{Workers: 4, Options: Options{Diagnostics: func(d Diagnostic) { paths[d.Path]++ }}}
+	summary, err := ConvertFS(context.Background(), fsys, t.TempDir(), opts)
+	if err != nil || summary.Converted != 20 || len(paths) != 20 || paths["7.rtf"] != 1 {
+		t.Error("expected diagnostics for each file", err, summary, paths)
+	}
+}
+
+func TestConvertFSCollision(t *testing.T) {
+	fsys := fstest.MapFS{
+		"x.RTF": {Data: []byte(`{\rtf1\pard\f0 upper\par}`)},
+		"x.rtf": {Data: []byte(`{\rtf1\pard\f0 lower\par}`)},
+	}
+	out := t.TempDir()
+	summary, err := ConvertFS(context.Background(), fsys, out, BatchOptions{})
+	if err != nil || summary.Converted != 1 || len(summary.Failed) != 1 || summary.Failed[0].Path != "x.rtf" {
+		t.Fatal("expected colliding file to fail", err, summary)
+	}
+	if data, err := os.ReadFile(filepath.Join(out, "x.txt")); err != nil || string(data) != "upper " {
+		t.Error("expected text of the first file", err, string(data))
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "a.txt")
+	for _, text := range []string{"one", "two"} {
+		if err := writeFileAtomic(name, []byte(text)); err != nil {
+			t.Fatal(err)
+		}
+		if data, err := os.ReadFile(name); err != nil || string(data) != text {
+			t.Error("expected file to be written", err, string(data))
+		}
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Error("expected no temporary files", err, entries)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "a.txt"), nil); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
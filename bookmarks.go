@@ -0,0 +1,61 @@
+package rtf2txt
+
+import (
+	"io"
+)
+
+// Bookmark is a named range of text marked by \bkmkstart and \bkmkend
+type Bookmark struct {
+	Name string
+	Text string
+
+	// Start and End are the character offsets of the bookmarked text in the
+	// extracted text
+	Start, End int
+}
+
+// Bookmarks is used to get the bookmarks from an io.Reader containing RTF
+// data in the order they start
+func Bookmarks(r io.Reader) ([]Bookmark, error) {
+	d, err := Convert(r, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return d.Bookmarks, nil
+}
+
+// handleBookmark updates the converter state for the bookmark destinations
+func (c *converter) handleBookmark(control string) {
+	switch control {
+	case "bkmkstart":
+		start := c.runes
+		c.capture(destBookmark, true, func(name string) {
+			c.bookmarks = append(c.bookmarks, Bookmark{Name: name, Start: start, End: -1})
+		})
+	case "bkmkend":
+		end := c.runes
+		c.capture(destBookmark, true, func(name string) {
+			for i := len(c.bookmarks) - 1; i >= 0; i-- {
+				if c.bookmarks[i].Name == name && c.bookmarks[i].End == -1 {
+					c.bookmarks[i].End = end
+					return
+				}
+			}
+		})
+	}
+}
+
+// anchorBookmarks sets the text of each bookmark once all of the document
+// text is known. Bookmarks which are never ended run to the end of the text
+func (c *converter) anchorBookmarks(text []rune) {
+	for i := range c.bookmarks {
+		b := &c.bookmarks[i]
+		if b.End == -1 || b.End > len(text) {
+			b.End = len(text)
+		}
+		if b.Start > b.End {
+			b.Start = b.End
+		}
+		b.Text = string(text[b.Start:b.End])
+	}
+}
@@ -0,0 +1,24 @@
+package rtf2txt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	const doc = `{\rtf1\pard\f0 Dear {\*\bkmkstart ClientName}\f0 Ren\'e9e Smith{\*\bkmkend ClientName}\f0 ,\par` +
+		`\pard\f0 Effective {\*\bkmkstart EffectiveDate}{\*\bkmkstart Empty}{\*\bkmkend Empty}\f0 1 May\par}`
+	bookmarks, err := Bookmarks(strings.NewReader(doc))
+	if err != nil || len(bookmarks) != 3 {
+		t.Fatal("expected bookmarks", err, bookmarks)
+	}
+	if b := bookmarks[0]; b.Name != "ClientName" || b.Text != "Renée Smith" || b.Start != 5 || b.End != 16 {
+		t.Error("expected client name", b)
+	}
+	if b := bookmarks[1]; b.Name != "EffectiveDate" || b.Text != "1 May " || b.Start != 28 || b.End != 34 {
+		t.Error("expected unterminated bookmark to run to the end", b)
+	}
+	if b := bookmarks[2]; b.Name != "Empty" || b.Text != "" || b.Start != 28 || b.End != 28 {
+		t.Error("expected empty bookmark", b)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/robarchibald/rtf2txt"
+)
+
+func batch(args []string, stdout, stderr io.Writer) int {
+	var f optionFlags
+	flags := newOptionFlags("batch", stderr, &f)
+	workers := flags.Int("workers", 0, "`number` of files converted at once, or 0 for one per CPU")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprintln(stderr, "usage: rtf2txt batch [flags] dir outdir")
+		return exitUsage
+	}
+	opts, err := f.options()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	summary, err := rtf2txt.ConvertDir(context.Background(), flags.Arg(0), flags.Arg(1), rtf2txt.BatchOptions{Options: opts, Workers: *workers})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitIO
+	}
+	writeSummary(stdout, summary)
+	code := exitOK
+	for _, failure := range summary.Failed {
+		if c := exitCode(failure.Err); c > code {
+			code = c
+		}
+	}
+	return code
+}
+
+func writeSummary(w io.Writer, s *rtf2txt.BatchSummary) {
+	fmt.Fprintf(w, "converted %d, skipped %d, failed %d\n", s.Converted, s.Skipped, len(s.Failed))
+	for _, failure := range s.Failed {
+		fmt.Fprintf(w, "  %s\n", failure.Error())
+	}
+}
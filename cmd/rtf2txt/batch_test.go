@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	in, out := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(in, "sub"), 0o755)
+	os.WriteFile(filepath.Join(in, "a.rtf"), []byte(`{\rtf1\pard\f0 one\par}`), 0o644)
+	os.WriteFile(filepath.Join(in, "sub", "bad.rtf"), []byte(`{\rtf1\pard\f0 hello\f463 hi`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"batch", "-workers", "2", "-paragraph", `\n`, in, out}, nil, &stdout, &stderr); code != exitParse {
+		t.Error("expected parse error", code, stderr.String())
+	}
+	expected := "converted 1, skipped 0, failed 1\n  sub/bad.rtf: Unexpected end of RTF data at line 1, column 29 (offset 28, depth 1) after \\fN\n"
+	if stdout.String() != expected {
+		t.Error("expected summary", stdout.String())
+	}
+	if data, err := os.ReadFile(filepath.Join(out, "a.txt")); err != nil || string(data) != "one\n" {
+		t.Error("expected text file", err, string(data))
+	}
+
+	stdout.Reset()
+	if code := run([]string{"batch", "-lenient", in, out}, nil, &stdout, &stderr); code != exitOK || stdout.String() != "converted 1, skipped 1, failed 0\n" {
+		t.Error("expected lenient batch", code, stdout.String())
+	}
+
+	if code := run([]string{"batch", filepath.Join(in, "missing"), out}, nil, &stdout, &stderr); code != exitIO {
+		t.Error("expected io error", code)
+	}
+	if code := run([]string{"batch", in}, nil, &stdout, &stderr); code != exitUsage {
+		t.Error("expected usage error", code)
+	}
+}
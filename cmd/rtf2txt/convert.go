@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/robarchibald/rtf2txt"
+)
+
+// convertFlags are the flags of the convert command
+type convertFlags struct {
+	optionFlags
+	output   string
+	format   string
+	encoding string
+}
+
+func newConvertFlags(stderr io.Writer) (*flag.FlagSet, *convertFlags) {
+	f := &convertFlags{}
+	flags := newOptionFlags("convert", stderr, &f.optionFlags)
+	flags.StringVar(&f.output, "o", "", "write to `file` instead of standard output")
+	flags.StringVar(&f.format, "format", "text", "output `format`: text or json")
+	flags.StringVar(&f.encoding, "encoding", "utf-8", "output `encoding`: utf-8, latin1 or ascii. Characters which can't be encoded are written as ?")
+	return flags, f
+}
+
+// options checks the flags and returns the conversion options
+func (f *convertFlags) options() (rtf2txt.Options, error) {
+	switch f.format {
+	case "text", "json":
+	default:
+		return f.opts, fmt.Errorf("unknown format %q", f.format)
+	}
+	switch f.encoding {
+	case "utf-8", "latin1", "ascii":
+	default:
+		return f.opts, fmt.Errorf("unknown encoding %q", f.encoding)
+	}
+	return f.optionFlags.options()
+}
+
+// optionFlags are the flags for the conversion options, which are shared
+// by the commands that convert documents
+type optionFlags struct {
+	paragraph string
+	revisions string
+	opts      rtf2txt.Options
+}
+
+func newOptionFlags(name string, stderr io.Writer, f *optionFlags) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&f.paragraph, "paragraph", " ", "`text` written at the end of each paragraph. \\n, \\r and \\t are escapes")
+	flags.StringVar(&f.revisions, "revisions", "accepted", "tracked changes `view`: accepted, rejected or marked")
+	flags.BoolVar(&f.opts.IncludeHidden, "hidden", false, "include hidden text")
+	flags.BoolVar(&f.opts.InlineComments, "comments", false, "write comments after the text they are anchored to")
+	flags.BoolVar(&f.opts.Lenient, "lenient", false, "return the text of truncated and malformed documents")
+	flags.Int64Var(&f.opts.Limits.MaxInputBytes, "max-input", 0, "maximum `bytes` of RTF in each file, or 0 for no limit")
+	return flags
+}
+
+// options checks the flags and returns the conversion options
+func (f *optionFlags) options() (rtf2txt.Options, error) {
+	switch f.revisions {
+	case "accepted":
+		f.opts.Revisions = rtf2txt.RevisionsAccepted
+	case "rejected":
+		f.opts.Revisions = rtf2txt.RevisionsRejected
+	case "marked":
+		f.opts.Revisions = rtf2txt.RevisionsMarked
+	default:
+		return f.opts, fmt.Errorf("unknown revisions view %q", f.revisions)
+	}
+	f.opts.ParagraphBreak = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t").Replace(f.paragraph)
+	return f.opts, nil
+}
+
+func convert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags, f := newConvertFlags(stderr)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	opts, err := f.options()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	code := exitOK
+	var out bytes.Buffer
+	docs := make(map[string]*rtf2txt.Document)
+	for _, name := range files {
+		d, err := convertFile(name, stdin, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			if c := exitCode(err); c > code {
+				code = c
+			}
+			continue
+		}
+		if f.format == "json" {
+			docs[name] = d
+			continue
+		}
+		out.WriteString(encode(d.Text, f.encoding))
+	}
+	if f.format == "json" {
+		e := json.NewEncoder(&out)
+		e.SetIndent("", "  ")
+		e.SetEscapeHTML(false)
+		if err := e.Encode(docs); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitIO
+		}
+	}
+	if err := writeOutput(f.output, stdout, out.Bytes()); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitIO
+	}
+	return code
+}
+
+func convertFile(name string, stdin io.Reader, opts rtf2txt.Options) (*rtf2txt.Document, error) {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return rtf2txt.Convert(r, opts)
+}
+
+// exitCode returns exitParse for documents which can't be converted and
+// exitIO for everything else
+func exitCode(err error) int {
+	var limitErr *rtf2txt.LimitError
+	if errors.Is(err, rtf2txt.ErrTruncated) || errors.Is(err, rtf2txt.ErrMalformedControl) || errors.As(err, &limitErr) {
+		return exitParse
+	}
+	return exitIO
+}
+
+// encode replaces the characters of text which can't be written in the
+// encoding with ?
+func encode(text, encoding string) string {
+	var limit rune
+	switch encoding {
+	case "latin1":
+		limit = 0xff
+	case "ascii":
+		limit = 0x7f
+	default:
+		return text
+	}
+	var b strings.Builder
+	for _, r := range text {
+		if r > limit || r == utf8.RuneError {
+			r = '?'
+		}
+		if encoding == "latin1" {
+			b.WriteByte(byte(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func writeOutput(name string, stdout io.Writer, data []byte) error {
+	if name == "" || name == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	doc := `{\rtf1\pard\f0 caf\'e9 one\par two\par}`
+	if code := run([]string{"convert"}, strings.NewReader(doc), &stdout, &stderr); code != exitOK || stdout.String() != "café one two " {
+		t.Errorf("expected text %q %d %s", stdout.String(), code, stderr.String())
+	}
+
+	stdout.Reset()
+	args := []string{"convert", "-paragraph", `\n`, "-encoding", "ascii"}
+	if code := run(args, strings.NewReader(doc), &stdout, &stderr); code != exitOK || stdout.String() != "caf? one\ntwo\n" {
+		t.Errorf("expected ascii text %q %d", stdout.String(), code)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"convert", "-encoding", "latin1"}, strings.NewReader(doc), &stdout, &stderr); code != exitOK || stdout.String() != "caf\xe9 one two " {
+		t.Errorf("expected latin1 text %q %d", stdout.String(), code)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"convert", "-format", "json", "../../testdata/list.rtf"}, nil, &stdout, &stderr); code != exitOK {
+		t.Error("expected success", code, stderr.String())
+	}
+	var docs map[string]struct{ Text string }
+	if err := json.Unmarshal(stdout.Bytes(), &docs); err != nil || !strings.HasPrefix(docs["../../testdata/list.rtf"].Text, "Intro 1. First") {
+		t.Error("expected json document", err, stdout.String())
+	}
+
+	output := filepath.Join(t.TempDir(), "out.txt")
+	if code := run([]string{"convert", "-o", output, "-"}, strings.NewReader(doc), &stdout, &stderr); code != exitOK {
+		t.Error("expected success", code, stderr.String())
+	}
+	if data, err := os.ReadFile(output); err != nil || string(data) != "café one two " {
+		t.Error("expected output file", err, string(data))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	if s := encode("café €", "latin1"); s != "caf\xe9 ?" {
+		t.Errorf("expected latin1 %q", s)
+	}
+	if s := encode("café €", "ascii"); s != "caf? ?" {
+		t.Errorf("expected ascii %q", s)
+	}
+	if s := encode("café €", "utf-8"); s != "café €" {
+		t.Errorf("expected utf-8 %q", s)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	const truncated = `{\rtf1\pard\f0 hello\par\f463 hi`
+	if code := run([]string{"convert"}, strings.NewReader(truncated), &stdout, &stderr); code != exitParse {
+		t.Error("expected parse error", code)
+	}
+	if !strings.HasPrefix(stderr.String(), "-: Unexpected end of RTF data at line 1") {
+		t.Error("expected error message", stderr.String())
+	}
+	stdout.Reset()
+	if code := run([]string{"convert", "-lenient"}, strings.NewReader(truncated), &stdout, &stderr); code != exitOK || stdout.String() != "hello hi" {
+		t.Error("expected lenient conversion", code, stdout.String())
+	}
+	stdout.Reset()
+	if code := run([]string{"convert", "-lenient", "-format", "json"}, strings.NewReader(truncated), &stdout, &stderr); code != exitOK ||
+		!strings.Contains(stdout.String(), `"Err": "Unexpected end of RTF data"`) {
+		t.Error("expected warning message in json", code, stdout.String())
+	}
+	if code := run([]string{"convert", "-max-input", "5"}, strings.NewReader(truncated), &stdout, &stderr); code != exitParse {
+		t.Error("expected limit error", code)
+	}
+	if code := run([]string{"convert", "missing.rtf"}, nil, &stdout, &stderr); code != exitIO {
+		t.Error("expected io error", code)
+	}
+	for _, args := range [][]string{{"-format", "pdf"}, {"-encoding", "ebcdic"}, {"-revisions", "all"}, {"-bogus"}} {
+		if code := run(append([]string{"convert"}, args...), nil, &stdout, &stderr); code != exitUsage {
+			t.Error("expected usage error", args, code)
+		}
+	}
+}
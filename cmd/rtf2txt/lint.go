@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/robarchibald/rtf2txt"
+)
+
+func lint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "write the findings as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	code := exitOK
+	reports := make(map[string][]rtf2txt.Finding)
+	for _, name := range files {
+		findings, err := lintFile(name, stdin)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			code = exitIO
+			continue
+		}
+		if len(findings) > 0 && code < exitParse {
+			code = exitParse
+		}
+		if *asJSON {
+			reports[name] = findings
+			continue
+		}
+		for _, f := range findings {
+			fmt.Fprintf(stdout, "%s:%d: %s\n", name, f.Offset, f.Message)
+		}
+	}
+	if *asJSON {
+		e := json.NewEncoder(stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(reports); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitIO
+		}
+	}
+	return code
+}
+
+func lintFile(name string, stdin io.Reader) ([]rtf2txt.Finding, error) {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return rtf2txt.Lint(r)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	good, bad := filepath.Join(dir, "good.rtf"), filepath.Join(dir, "bad.rtf")
+	os.WriteFile(good, []byte(`{\rtf1{\fonttbl{\f0 Arial;}}\f0 hello\par}`), 0o644)
+	os.WriteFile(bad, []byte(`{\rtf1\f1 hello}}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"lint", good}, nil, &stdout, &stderr); code != exitOK || stdout.Len() != 0 {
+		t.Error("expected no findings", code, stdout.String())
+	}
+	if code := run([]string{"lint", good, bad}, nil, &stdout, &stderr); code != exitParse {
+		t.Error("expected findings", code)
+	}
+	expected := bad + ":6: \\f1 is not in the font table\n" + bad + ":16: closing brace without an open group\n"
+	if stdout.String() != expected {
+		t.Error("expected findings with offsets", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"lint", "-json"}, strings.NewReader(`{\rtf1 a`), &stdout, &stderr); code != exitParse ||
+		!strings.Contains(stdout.String(), `"Message": "group is not closed"`) {
+		t.Error("expected JSON findings", code, stdout.String())
+	}
+	if code := run([]string{"lint", filepath.Join(dir, "missing.rtf")}, nil, &stdout, &stderr); code != exitIO {
+		t.Error("expected io error", code)
+	}
+}
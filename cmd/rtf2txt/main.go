@@ -0,0 +1,167 @@
+// Command rtf2txt works with RTF documents from the command line.
+//
+// Usage:
+//
+//	rtf2txt convert [flags] [file ...]
+//	rtf2txt batch [flags] dir outdir
+//	rtf2txt serve [flags]
+//	rtf2txt analyze [-json] [file ...]
+//	rtf2txt lint [-json] [file ...]
+//
+// convert writes the text of each file to standard output or to the file
+// given with -o. The flags select the conversion options and the output
+// format and encoding. Run rtf2txt convert -h to list them.
+//
+// batch converts every .rtf file under dir to a .txt file with the same path
+// under outdir using several workers. Files whose text is already up to
+// date are skipped. A summary with the error for each file which failed is
+// written to standard output.
+//
+// serve converts RTF uploaded to POST /convert, either as the request body
+// or as the first file of a multipart form. The Accept header selects text,
+// JSON, Markdown or HTML. GET /healthz and GET /metrics report the state of
+// the server. Uploads are limited by -max-size, and reading and converting
+// them by -timeout.
+//
+// analyze reports the OLE objects and anything unusual found in each file
+// without opening or running any embedded content.
+//
+// lint checks the structure of each file and writes each problem found with
+// its byte offset, such as unbalanced braces, \binN longer than the file or
+// fonts which aren't in the font table.
+//
+// convert, analyze and lint read standard input when no files are given.
+// The exit code is 1 when a document can't be converted or lint finds a
+// problem, 2 for usage errors and 3 when a file can't be read or written.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/robarchibald/rtf2txt"
+)
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitParse = 1
+	exitUsage = 2
+	exitIO    = 3
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	switch args[0] {
+	case "convert":
+		return convert(args[1:], stdin, stdout, stderr)
+	case "batch":
+		return batch(args[1:], stdout, stderr)
+	case "serve":
+		return serve(args[1:], stderr)
+	case "analyze":
+		return analyze(args[1:], stdin, stdout, stderr)
+	case "lint":
+		return lint(args[1:], stdin, stdout, stderr)
+	default:
+		usage(stderr)
+		return exitUsage
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: rtf2txt convert [flags] [file ...]")
+	fmt.Fprintln(w, "       rtf2txt batch [flags] dir outdir")
+	fmt.Fprintln(w, "       rtf2txt serve [flags]")
+	fmt.Fprintln(w, "       rtf2txt analyze [-json] [file ...]")
+	fmt.Fprintln(w, "       rtf2txt lint [-json] [file ...]")
+}
+
+func analyze(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "write the reports as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	code := exitOK
+	reports := make(map[string]*rtf2txt.Analysis)
+	for _, name := range files {
+		a, err := analyzeFile(name, stdin)
+		if a == nil {
+			fmt.Fprintln(stderr, err)
+			code = exitIO
+			continue
+		}
+		if *asJSON {
+			reports[name] = a
+			continue
+		}
+		writeAnalysis(stdout, name, a)
+	}
+	if *asJSON {
+		e := json.NewEncoder(stdout)
+		e.SetIndent("", "  ")
+		if err := e.Encode(reports); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitIO
+		}
+	}
+	return code
+}
+
+// analyzeFile returns nil when the file can't be read. Parse errors are
+// part of the analysis
+func analyzeFile(name string, stdin io.Reader) (*rtf2txt.Analysis, error) {
+	r := stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	a, _ := rtf2txt.Analyze(r)
+	return a, nil
+}
+
+func writeAnalysis(w io.Writer, name string, a *rtf2txt.Analysis) {
+	fmt.Fprintln(w, name)
+	fmt.Fprintf(w, "  header: %q\n", a.Header)
+	fmt.Fprintf(w, "  max depth: %d\n", a.MaxDepth)
+	for _, o := range a.Objects {
+		fmt.Fprintf(w, "  object at %d: class %q, %d bytes", o.Offset, o.Class, o.Size)
+		if o.Linked {
+			fmt.Fprint(w, ", linked")
+		}
+		if o.Update {
+			fmt.Fprint(w, ", \\objupdate")
+		}
+		if o.AutoLink {
+			fmt.Fprint(w, ", \\objautlink")
+		}
+		fmt.Fprintln(w)
+		for _, anomaly := range o.Anomalies {
+			fmt.Fprintf(w, "    %s\n", anomaly)
+		}
+	}
+	for _, f := range a.Findings {
+		fmt.Fprintf(w, "  at %d: %s\n", f.Offset, f.Message)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	doc := `{\rtf1{\object\objautlink{\*\objclass Equation.3}}}`
+	if code := run([]string{"analyze"}, strings.NewReader(doc), &stdout, &stderr); code != exitOK {
+		t.Error("expected success", code, stderr.String())
+	}
+	expected := `-
+  header: "{\\rtf1"
+  max depth: 3
+  object at 7: class "Equation.3", 0 bytes, \objautlink
+    Equation Editor object, which is commonly exploited
+`
+	if stdout.String() != expected {
+		t.Error("expected report", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"analyze", "-json", "../../testdata/ad.rtf"}, nil, &stdout, &stderr); code != exitOK {
+		t.Error("expected success", code, stderr.String())
+	}
+	var reports map[string]struct{ Header string }
+	if err := json.Unmarshal(stdout.Bytes(), &reports); err != nil || reports["../../testdata/ad.rtf"].Header != `{\rtf1` {
+		t.Error("expected json report", err, stdout.String())
+	}
+
+	if code := run([]string{"analyze", "missing.rtf"}, nil, &stdout, &stderr); code != exitIO {
+		t.Error("expected io error", code)
+	}
+	if code := run(nil, nil, &stdout, &stderr); code != exitUsage {
+		t.Error("expected usage error", code)
+	}
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/robarchibald/rtf2txt"
+)
+
+// multipartOverhead is the size allowed for the headers and other parts of
+// a multipart upload on top of the size of the RTF data
+const multipartOverhead = 64 << 10
+
+// Formats which can be requested with the Accept header, in order of
+// preference when the client accepts any of them
+var formats = []string{"text/plain", "application/json", "text/markdown", "text/html"}
+
+func serve(args []string, stderr io.Writer) int {
+	var f optionFlags
+	flags := newOptionFlags("serve", stderr, &f)
+	addr := flags.String("addr", ":8080", "`address` to listen on")
+	maxSize := flags.Int64("max-size", 10<<20, "maximum `bytes` of an upload")
+	timeout := flags.Duration("timeout", 30*time.Second, "maximum `duration` of reading and converting an upload")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	opts, err := f.options()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+	idle := time.Minute
+	if *timeout > idle {
+		idle = *timeout
+	}
+	s := &http.Server{Addr: *addr, Handler: newServer(opts, *maxSize, *timeout),
+		ReadHeaderTimeout: 10 * time.Second, IdleTimeout: idle}
+	fmt.Fprintln(stderr, "listening on", *addr)
+	if err := s.ListenAndServe(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitIO
+	}
+	return exitOK
+}
+
+// server converts RTF uploaded to /convert. It also serves /healthz and
+// /metrics
+type server struct {
+	http.ServeMux
+	opts    rtf2txt.Options
+	maxSize int64
+	timeout time.Duration
+
+	requests, failures, bytes atomic.Int64
+	nanoseconds               atomic.Int64 // time spent converting
+}
+
+func newServer(opts rtf2txt.Options, maxSize int64, timeout time.Duration) *server {
+	s := &server{opts: opts, maxSize: maxSize, timeout: timeout}
+	s.HandleFunc("/convert", allow("POST", s.convert))
+	s.HandleFunc("/healthz", allow("GET", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "ok\n") }))
+	s.HandleFunc("/metrics", allow("GET", s.metrics))
+	return s
+}
+
+// allow returns a handler which only passes requests with the method to h.
+// GET allows HEAD too
+func allow(method string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method && (method != "GET" || r.Method != "HEAD") {
+			w.Header().Set("Allow", method)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *server) convert(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	// the upload is read while converting, so a client which sends it slowly
+	// is stopped by the timeout too. The response can take as long again
+	deadline := time.Now().Add(s.timeout)
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(deadline)
+	rc.SetWriteDeadline(deadline.Add(s.timeout))
+	format := negotiate(r.Header.Get("Accept"))
+	if format == "" {
+		s.fail(w, http.StatusNotAcceptable, "Accept must allow one of "+strings.Join(formats, ", "))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxSize+multipartOverhead)
+	body, err := upload(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.fail(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer body.Close()
+
+	opts := s.opts
+	if max := opts.Limits.MaxInputBytes; max <= 0 || max > s.maxSize {
+		opts.Limits.MaxInputBytes = s.maxSize
+	}
+	if format == "text/markdown" || format == "text/html" {
+		opts.ParagraphBreak = "\n\n"
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+	defer cancel()
+	start := time.Now()
+	counted := &countingReader{r: body}
+	d, err := rtf2txt.ConvertContext(ctx, counted, opts)
+	s.nanoseconds.Add(int64(time.Since(start)))
+	s.bytes.Add(counted.n)
+	if err != nil {
+		var limitErr *rtf2txt.LimitError
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &limitErr), errors.As(err, &tooLarge):
+			s.fail(w, http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, os.ErrDeadlineExceeded):
+			s.fail(w, http.StatusRequestTimeout, "upload took longer than "+s.timeout.String())
+		case errors.Is(err, context.DeadlineExceeded):
+			s.fail(w, http.StatusServiceUnavailable, "conversion took longer than "+s.timeout.String())
+		case exitCode(err) == exitParse:
+			s.fail(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			s.fail(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", format+"; charset=utf-8")
+	switch format {
+	case "application/json":
+		e := json.NewEncoder(w)
+		e.SetEscapeHTML(false)
+		e.Encode(d)
+	case "text/markdown":
+		io.WriteString(w, markdown(d.Text))
+	case "text/html":
+		io.WriteString(w, htmlText(d.Text))
+	default:
+		io.WriteString(w, d.Text)
+	}
+}
+
+func (s *server) fail(w http.ResponseWriter, status int, message string) {
+	s.failures.Add(1)
+	http.Error(w, message, status)
+}
+
+func (s *server) metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "rtf2txt_requests_total %d\n", s.requests.Load())
+	fmt.Fprintf(w, "rtf2txt_failures_total %d\n", s.failures.Load())
+	fmt.Fprintf(w, "rtf2txt_input_bytes_total %d\n", s.bytes.Load())
+	fmt.Fprintf(w, "rtf2txt_conversion_seconds_total %g\n", time.Duration(s.nanoseconds.Load()).Seconds())
+}
+
+// upload returns the RTF data of the request. A multipart form uses its
+// first file. Any other body is the RTF data
+func upload(r *http.Request) (io.ReadCloser, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, nil
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("multipart form has no file")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if p.FileName() != "" {
+			return p, nil
+		}
+		p.Close()
+	}
+}
+
+// negotiate returns the format preferred by the Accept header, or "" when
+// none of them are accepted
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return formats[0]
+	}
+	type choice struct {
+		format string
+		q      float64
+		order  int
+	}
+	var choices []choice
+	for i, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		for j, f := range formats {
+			if mediaType == f || mediaType == "*/*" || mediaType == f[:strings.IndexByte(f, '/')]+"/*" {
+				choices = append(choices, choice{f, q, i*len(formats) + j})
+			}
+		}
+	}
+	if len(choices) == 0 {
+		return ""
+	}
+	sort.SliceStable(choices, func(i, j int) bool {
+		if choices[i].q != choices[j].q {
+			return choices[i].q > choices[j].q
+		}
+		return choices[i].order < choices[j].order
+	})
+	return choices[0].format
+}
+
+// paragraphs splits text converted with "\n\n" paragraph breaks into the
+// lines of each paragraph, dropping empty paragraphs
+func paragraphs(text string) [][]string {
+	var result [][]string
+	for _, p := range strings.Split(text, "\n\n") {
+		if strings.TrimSpace(p) != "" {
+			result = append(result, strings.Split(strings.Trim(p, "\n"), "\n"))
+		}
+	}
+	return result
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+	"#", `\#`, "<", `\<`, ">", `\>`, "|", `\|`)
+
+// escapeMarkdown escapes the characters of line which Markdown would read as
+// formatting, including list markers at its start
+func escapeMarkdown(line string) string {
+	line = markdownEscaper.Replace(line)
+	if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+") {
+		return `\` + line
+	}
+	if i := strings.IndexFunc(line, func(r rune) bool { return r < '0' || r > '9' }); i > 0 && (line[i] == '.' || line[i] == ')') {
+		return line[:i] + `\` + line[i:]
+	}
+	return line
+}
+
+// markdown writes each paragraph as a Markdown paragraph with hard line
+// breaks between its lines
+func markdown(text string) string {
+	var b strings.Builder
+	for i, lines := range paragraphs(text) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for j, line := range lines {
+			b.WriteString(escapeMarkdown(strings.TrimSpace(line)))
+			if j < len(lines)-1 {
+				b.WriteString(`\`)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// htmlText writes each paragraph as an HTML paragraph with <br> between its
+// lines
+func htmlText(text string) string {
+	var b strings.Builder
+	for _, lines := range paragraphs(text) {
+		b.WriteString("<p>")
+		for j, line := range lines {
+			if j > 0 {
+				b.WriteString("<br>")
+			}
+			b.WriteString(html.EscapeString(strings.TrimSpace(line)))
+		}
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robarchibald/rtf2txt"
+)
+
+func TestServe(t *testing.T) {
+	s := newServer(rtf2txt.Options{}, 1<<10, time.Minute)
+	const doc = `{\rtf1\pard\f0 1. <Intro> *one*\line two\par\par\f0 three\par}`
+	tests := []struct {
+		accept, contentType, body string
+	}{
+		{"", "text/plain; charset=utf-8", "1. <Intro> *one*\ntwo  three "},
+		{"text/markdown, text/plain;q=0.5", "text/markdown; charset=utf-8", "1\\. \\<Intro\\> \\*one\\*\\\ntwo\n\nthree\n"},
+		{"text/*;q=0.1, text/html", "text/html; charset=utf-8", "<p>1. &lt;Intro&gt; *one*<br>two</p>\n<p>three</p>\n"},
+		{"application/json", "application/json; charset=utf-8", `{"Text":"1. <Intro> *one*\ntwo  three ",`},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/convert", strings.NewReader(doc))
+		r.Header.Set("Accept", test.accept)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != test.contentType || !strings.HasPrefix(w.Body.String(), test.body) {
+			t.Errorf("expected %s response %d %q", test.accept, w.Code, w.Body.String())
+		}
+	}
+
+	var body bytes.Buffer
+	m := multipart.NewWriter(&body)
+	m.WriteField("name", "value")
+	f, _ := m.CreateFormFile("file", "doc.rtf")
+	f.Write([]byte(doc))
+	m.Close()
+	r := httptest.NewRequest("POST", "/convert", &body)
+	r.Header.Set("Content-Type", m.FormDataContentType())
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "1. <Intro> *one*\ntwo  three " {
+		t.Error("expected multipart upload", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "ok\n" {
+		t.Error("expected health check", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if !strings.Contains(w.Body.String(), "rtf2txt_requests_total 5\nrtf2txt_failures_total 0\n") {
+		t.Error("expected metrics", w.Body.String())
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	s := newServer(rtf2txt.Options{}, 1<<10, time.Minute)
+	tests := []struct {
+		accept, contentType, body string
+		code                      int
+	}{
+		{"image/png", "", `{\rtf1 hi}`, http.StatusNotAcceptable},
+		{"", "", `{\rtf1\pard\f0 hello\f463 hi`, http.StatusUnprocessableEntity},
+		{"", "", `{\rtf1\pard\f0 ` + strings.Repeat("x", 2<<10) + `}`, http.StatusRequestEntityTooLarge},
+		{"", "multipart/form-data; boundary=x", "--x--\r\n", http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/convert", strings.NewReader(test.body))
+		r.Header.Set("Accept", test.accept)
+		r.Header.Set("Content-Type", test.contentType)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, r)
+		if w.Code != test.code {
+			t.Error("expected error", test.code, w.Code, w.Body.String())
+		}
+	}
+
+	s = newServer(rtf2txt.Options{}, 1<<20, time.Nanosecond)
+	r := httptest.NewRequest("POST", "/convert", strings.NewReader(`{\rtf1\pard\f0 `+strings.Repeat(`word\par `, 10000)+`}`))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Error("expected timeout", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("GET", "/convert", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Error("expected method not allowed", w.Code)
+	}
+}
+
+func TestServeSlowUpload(t *testing.T) {
+	ts := httptest.NewServer(newServer(rtf2txt.Options{}, 1<<20, 100*time.Millisecond))
+	defer ts.Close()
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// only part of the body is sent
+	if _, err := conn.Write([]byte("POST /convert HTTP/1.1\r\nHost: test\r\nContent-Length: 1000\r\n\r\n{\\rtf1 hel")); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal("expected response before the body is sent", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestTimeout {
+		t.Error("expected request timeout", resp.StatusCode)
+	}
+}
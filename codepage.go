@@ -0,0 +1,39 @@
+package rtf2txt
+
+import "unicode/utf8"
+
+// defaultCodePage is the code page of documents which don't set one, as
+// \ansi is the default character set
+const defaultCodePage = 1252
+
+// characterSets are the code pages of the character set control words.
+// \ansicpgN can follow \ansi to change it
+var characterSets = map[string]int{"ansi": 1252, "mac": 10000, "pc": 437, "pca": 850}
+
+// windows1252 are the characters of the bytes 0x80 to 0x9f in Windows-1252.
+// The other bytes are the same in ISO-8859-1, and the bytes which aren't
+// defined are kept
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+// decodeCodePage returns the text of a \'hh escape from getUnicode with the
+// byte decoded in the code page. Code pages other than Windows-1252 are read
+// as ISO-8859-1
+func decodeCodePage(text string, codePage int) string {
+	r, size := utf8.DecodeRuneInString(text)
+	if codePage != 1252 || r < 0x80 || r > 0x9f {
+		return text
+	}
+	return string(windows1252[r-0x80]) + text[size:]
+}
+
+// codePageDecoding returns the name of the code page bytes are decoded with
+// for a code page
+func codePageDecoding(codePage int) string {
+	if codePage == 1252 {
+		return "Windows-1252"
+	}
+	return "ISO-8859-1"
+}
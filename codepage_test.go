@@ -0,0 +1,33 @@
+package rtf2txt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodePage(t *testing.T) {
+	tests := []struct {
+		doc, expected string
+	}{
+		{`{\rtf1\ansi\ansicpg1252 \'93caf\'e9\'94 \'97 x\par}`, "“café” — x "},
+		{`{\rtf1 \'97 x}`, "— x"},
+		{`{\rtf1\ansi\ansicpg28591 \'97 x}`, "\u0097 x"},
+		{`{\rtf1\mac \'97 x}`, "\u0097 x"},
+		{`{\rtf1\ansi\ansicpg1252 \'81\'9d}`, "\u0081\u009d"},
+	}
+	for _, test := range tests {
+		r, err := Text(strings.NewReader(test.doc))
+		if err != nil || r.String() != test.expected {
+			t.Errorf("%s: expected %q, got %q %v", test.doc, test.expected, r, err)
+		}
+	}
+}
+
+func TestDecodeCodePage(t *testing.T) {
+	if s := decodeCodePage("\u0080x", 1252); s != "€x" {
+		t.Errorf("expected euro %q", s)
+	}
+	if s := decodeCodePage("\u0080x", 28591); s != "\u0080x" {
+		t.Errorf("expected ISO-8859-1 %q", s)
+	}
+}
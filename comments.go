@@ -0,0 +1,95 @@
+package rtf2txt
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comment is a Word comment (annotation) found in an RTF document
+type Comment struct {
+	ID       string    // \atnref, which matches the \atrfstart and \atrfend of the anchor
+	Author   string    // \atnauthor
+	Initials string    // \atnid
+	Date     time.Time // \atndate
+	Text     string    // body of the comment
+
+	// Anchor is the document text the comment is attached to. Start and End
+	// are its character offsets in the extracted text. Comments without an
+	// anchor have Start equal to End at the position of the comment
+	Anchor     string
+	Start, End int
+}
+
+// Comments is used to get the comments from an io.Reader containing RTF data
+func Comments(r io.Reader) ([]Comment, error) {
+	d, err := Convert(r, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return d.Comments, nil
+}
+
+// handleComment updates the converter state for the comment destinations
+func (c *converter) handleComment(control string) {
+	switch control {
+	case "atnid":
+		c.capture(destComment, true, func(text string) { c.comment.Initials = text })
+	case "atnauthor":
+		c.capture(destComment, true, func(text string) { c.comment.Author = text })
+	case "atnref":
+		c.capture(destComment, true, func(text string) { c.comment.ID = text })
+	case "atndate":
+		c.capture(destComment, true, func(text string) { c.comment.Date = parseDTTM(text) })
+	case "atrfstart":
+		start := c.runes
+		c.capture(destComment, true, func(text string) { c.anchors[text] = [2]int{start, -1} })
+	case "atrfend":
+		end := c.runes
+		c.capture(destComment, true, func(text string) {
+			anchor := c.anchors[text]
+			c.anchors[text] = [2]int{anchor[0], end}
+		})
+	case "annotation":
+		c.capture(destComment, false, func(text string) {
+			comment := c.comment
+			comment.Text = strings.TrimSpace(text)
+			comment.Start, comment.End = c.runes, c.runes
+			c.comments = append(c.comments, comment)
+			c.comment = Comment{}
+			if c.opts.InlineComments {
+				c.write("[Comment " + comment.Author + ": " + comment.Text + "]")
+			}
+		})
+	}
+}
+
+// anchorComments sets the anchored text of each comment once all of the
+// document text is known
+func (c *converter) anchorComments(text []rune) {
+	for i := range c.comments {
+		anchor, ok := c.anchors[c.comments[i].ID]
+		if !ok || anchor[1] < anchor[0] || anchor[1] > len(text) {
+			continue
+		}
+		c.comments[i].Start, c.comments[i].End = anchor[0], anchor[1]
+		c.comments[i].Anchor = string(text[anchor[0]:anchor[1]])
+	}
+}
+
+// parseDTTM parses the packed date and time format used by \atndate and
+// the revision dates
+func parseDTTM(text string) time.Time {
+	v, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	if err != nil || v == 0 {
+		return time.Time{}
+	}
+	v &= 0xFFFFFFFF // written as a signed long
+	minute := int(v & 0x3F)
+	hour := int(v >> 6 & 0x1F)
+	day := int(v >> 11 & 0x1F)
+	month := time.Month(v >> 16 & 0xF)
+	year := 1900 + int(v>>20&0x1FF)
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
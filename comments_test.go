@@ -0,0 +1,47 @@
+package rtf2txt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const commentDoc = `{\rtf1\pard\f0 The fee is {\*\atrfstart 1}\f0 ten dollars{\*\atrfend 1}\f0  per month.` +
+	`{\*\atnid JD}{\*\atnauthor John Doe}\chatn {\*\annotation{\*\atnref 1}{\*\atndate -1480361314}\pard\plain \f0 Should be twelve.\par}` +
+	`\par}`
+
+func TestComments(t *testing.T) {
+	comments, err := Comments(strings.NewReader(commentDoc))
+	if err != nil || len(comments) != 1 {
+		t.Fatal("expected comment", err, comments)
+	}
+	c := comments[0]
+	if c.ID != "1" || c.Author != "John Doe" || c.Initials != "JD" || c.Text != "Should be twelve." {
+		t.Error("expected comment details", c)
+	}
+	if !c.Date.Equal(time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Error("expected comment date", c.Date)
+	}
+	if c.Anchor != "ten dollars" || c.Start != 11 || c.End != 22 {
+		t.Error("expected anchored text", c.Anchor, c.Start, c.End)
+	}
+
+	r, err := TextWithOptions(strings.NewReader(commentDoc), Options{InlineComments: true})
+	if err != nil || r.String() != "The fee is ten dollars per month.[Comment John Doe: Should be twelve.] " {
+		t.Error("expected inline comment", err, r.String())
+	}
+
+	r, err = Text(strings.NewReader(commentDoc))
+	if err != nil || r.String() != "The fee is ten dollars per month. " {
+		t.Error("expected comment to be excluded from text", err, r.String())
+	}
+}
+
+func TestParseDTTM(t *testing.T) {
+	if d := parseDTTM("2814605982"); !d.Equal(time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)) {
+		t.Error("expected date", d)
+	}
+	if d := parseDTTM("bad"); !d.IsZero() {
+		t.Error("expected zero date", d)
+	}
+}
@@ -0,0 +1,118 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// Compression types of compressed RTF
+const (
+	compressedLZFu = 0x75465a4c // "LZFu"
+	compressedMELA = 0x414c454d // "MELA", which isn't compressed
+)
+
+// lzfuDictionary is the start of the dictionary of LZFu compressed RTF
+const lzfuDictionary = `{\rtf1\ansi\mac\deff0\deftab720{\fonttbl;}{\f0\fnil \froman \fswiss \fmodern \fscript \fdecor MS Sans SerifSymbolArialTimes New RomanCourier{\colortbl\red0\green0\blue0` +
+	"\r\n" + `\par \pard\plain\f0\fs20\b\i\u\tab\tx`
+
+// ErrCompressedCRC is returned when LZFu compressed RTF doesn't match its CRC
+var ErrCompressedCRC = errors.New("Compressed RTF doesn't match its CRC")
+
+// Decompress is used to get the RTF from an io.Reader containing compressed
+// RTF, such as the PR_RTF_COMPRESSED property of Outlook messages. Both the
+// LZFu and the uncompressed MELA formats are supported
+func Decompress(r io.Reader) ([]byte, error) {
+	var header struct {
+		CompSize, RawSize, CompType, CRC uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("Invalid compressed RTF header. %w", err)
+	}
+	if header.CompSize < 12 {
+		return nil, fmt.Errorf("Invalid compressed RTF size %d", header.CompSize)
+	}
+	var data bytes.Buffer // grows with the data actually read rather than the size claimed
+	if _, err := io.CopyN(&data, r, int64(header.CompSize-12)); err != nil {
+		return nil, fmt.Errorf("Compressed RTF is truncated. %w", err)
+	}
+
+	switch header.CompType {
+	case compressedMELA:
+		return truncateRaw(data.Bytes(), header.RawSize), nil
+	case compressedLZFu:
+		if crc := compressedCRC(data.Bytes()); crc != header.CRC {
+			return nil, fmt.Errorf("%w. CRC is 0x%08x instead of 0x%08x", ErrCompressedCRC, crc, header.CRC)
+		}
+		return decompressLZFu(data.Bytes(), header.RawSize)
+	default:
+		return nil, fmt.Errorf("Unknown compressed RTF type 0x%08x", header.CompType)
+	}
+}
+
+// decompressLZFu expands LZFu data. Each control byte is followed by eight
+// items, starting with its lowest bit. A 0 bit is a literal byte and a 1 bit
+// is a big endian reference to the dictionary with a 12 bit offset and a 4
+// bit length less 2. A reference to the current write offset ends the data
+func decompressLZFu(data []byte, rawSize uint32) ([]byte, error) {
+	var dict [4096]byte
+	copy(dict[:], lzfuDictionary)
+	write := len(lzfuDictionary)
+	var out bytes.Buffer
+	for i := 0; i < len(data); {
+		control := data[i]
+		i++
+		for bit := 0; bit < 8; bit++ {
+			if i >= len(data) {
+				return nil, errors.New("Compressed RTF ends without its end marker")
+			}
+			if control&(1<<bit) == 0 {
+				out.WriteByte(data[i])
+				dict[write] = data[i]
+				write = (write + 1) % len(dict)
+				i++
+				continue
+			}
+			if i+1 >= len(data) {
+				return nil, errors.New("Compressed RTF ends in a dictionary reference")
+			}
+			ref := int(data[i])<<8 | int(data[i+1])
+			i += 2
+			offset, length := ref>>4, ref&0xf+2
+			if offset == write {
+				return truncateRaw(out.Bytes(), rawSize), nil
+			}
+			for j := 0; j < length; j++ { // the reference can overlap the bytes being written
+				b := dict[(offset+j)%len(dict)]
+				out.WriteByte(b)
+				dict[write] = b
+				write = (write + 1) % len(dict)
+			}
+		}
+		if int64(out.Len()) > int64(rawSize)+8*17 { // more than one run past the size claimed
+			return nil, fmt.Errorf("Compressed RTF is larger than its size %d", rawSize)
+		}
+	}
+	return nil, errors.New("Compressed RTF ends without its end marker")
+}
+
+// truncateRaw drops any padding after the RTF
+func truncateRaw(raw []byte, rawSize uint32) []byte {
+	if uint32(len(raw)) > rawSize {
+		return raw[:rawSize]
+	}
+	return raw
+}
+
+// compressedCRC is the CRC-32 of compressed RTF, which starts at 0 and
+// isn't inverted at the end
+func compressedCRC(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc = crc32.IEEETable[byte(crc)^b] ^ crc>>8
+	}
+	return crc
+}
@@ -0,0 +1,55 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// compressedExample is the example of LZFu compressed RTF from [MS-OXRTFCP]
+var compressedExample = []byte{
+	0x2d, 0x00, 0x00, 0x00, 0x2b, 0x00, 0x00, 0x00, 0x4c, 0x5a, 0x46, 0x75, 0xf1, 0xc5, 0xc7, 0xa7,
+	0x03, 0x00, 0x0a, 0x00, 0x72, 0x63, 0x70, 0x67, 0x31, 0x32, 0x35, 0x42, 0x32, 0x0a, 0xf3, 0x20,
+	0x68, 0x65, 0x6c, 0x09, 0x00, 0x20, 0x62, 0x77, 0x05, 0xb0, 0x6c, 0x64, 0x7d, 0x0a, 0x80, 0x0f,
+	0xa0,
+}
+
+func TestDecompress(t *testing.T) {
+	raw, err := Decompress(bytes.NewReader(compressedExample))
+	if err != nil || string(raw) != "{\\rtf1\\ansi\\ansicpg1252\\pard hello world}\r\n" {
+		t.Fatalf("expected decompressed rtf %q %v", raw, err)
+	}
+	if text, err := Text(bytes.NewReader(raw)); err != nil || text.String() != "hello world" {
+		t.Errorf("expected text %q %v", text, err)
+	}
+
+	corrupt := append([]byte{}, compressedExample...)
+	corrupt[20] ^= 0xff
+	if _, err := Decompress(bytes.NewReader(corrupt)); !errors.Is(err, ErrCompressedCRC) {
+		t.Error("expected crc error", err)
+	}
+	if _, err := Decompress(bytes.NewReader(compressedExample[:30])); err == nil {
+		t.Error("expected truncated error")
+	}
+	if _, err := Decompress(bytes.NewReader(compressedExample[:10])); err == nil {
+		t.Error("expected header error")
+	}
+
+	mela := []byte(`{\rtf1 plain}`)
+	header := []uint32{uint32(len(mela) + 12), uint32(len(mela)), compressedMELA, 0}
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, header)
+	b.Write(mela)
+	if raw, err := Decompress(&b); err != nil || !bytes.Equal(raw, mela) {
+		t.Errorf("expected uncompressed rtf %q %v", raw, err)
+	}
+
+	header[2] = 0x12345678
+	b.Reset()
+	binary.Write(&b, binary.LittleEndian, header)
+	b.Write(mela)
+	if _, err := Decompress(&b); err == nil {
+		t.Error("expected unknown type error")
+	}
+}
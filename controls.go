@@ -0,0 +1,401 @@
+package rtf2txt
+
+import "strings"
+
+// knownControls are the control words of the RTF specification from
+// "RTF control codes.xlsm", named like tokenizeControl names them. The value
+// is whether the control word is a destination
+var knownControls = map[string]bool{
+	"ApplyBrkRules": false, "ab": false, "abshN": false, "abslock": false, "absnoovrlpN": false,
+	"abswN": false, "acaps": false, "acccircle": false, "acccomma": false, "accdot": false,
+	"accnone": false, "accunderdot": false, "acfN": false, "additive": false, "adeffN": false,
+	"adeflangN": false, "adjustright": false, "adnN": false, "aenddoc": false, "aendnotes": false,
+	"aexpndN": false, "afN": false, "afelev": false, "afsN": false, "aftnbj": false, "aftncn": true,
+	"aftnnalc": false, "aftnnar": false, "aftnnauc": false, "aftnnchi": false, "aftnnchosung": false,
+	"aftnncnum": false, "aftnndbar": false, "aftnndbnum": false, "aftnndbnumd": false,
+	"aftnndbnumk": false, "aftnndbnumt": false, "aftnnganada": false, "aftnngbnum": false,
+	"aftnngbnumd": false, "aftnngbnumk": false, "aftnngbnuml": false, "aftnnrlc": false,
+	"aftnnruc": false, "aftnnzodiac": false, "aftnnzodiacd": false, "aftnnzodiacl": false,
+	"aftnrestart": false, "aftnrstcont": false, "aftnsep": true, "aftnsepc": true, "aftnstartN": false,
+	"aftntj": false, "ai": false, "alangN": false, "allowfieldendsel": false, "allprot": false,
+	"alntblind": false, "alt": false, "animtextN": false, "annotation": true, "annotprot": false,
+	"ansi": false, "ansicpgN": false, "aoutl": false, "ascaps": false, "ashad": false,
+	"asianbrkrule": false, "aspalpha": false, "aspnum": false, "astrike": false, "atnauthor": true,
+	"atndate": true, "atnicn": true, "atnid": true, "atnparent": true, "atnref": true, "atntime": true,
+	"atrfend": true, "atrfstart": true, "aul": false, "auld": false, "auldb": false, "aulnone": false,
+	"aulw": false, "aupN": false, "author": true, "autofmtoverride": false, "b": false,
+	"background": true, "bdbfhdr": false, "bdrrlswsix": false, "bgbdiag": false, "bgcross": false,
+	"bgdcross": false, "bgdkbdiag": false, "bgdkcross": false, "bgdkdcross": false, "bgdkfdiag": false,
+	"bgdkhoriz": false, "bgdkvert": false, "bgfdiag": false, "bghoriz": false, "bgvert": false,
+	"binN": false, "binfsxnN": false, "binsxnN": false, "bkmkcolfN": false, "bkmkcollN": false,
+	"bkmkend": true, "bkmkpub": false, "bkmkstart": true, "bliptagN": false, "blipuid": true,
+	"blipupiN": false, "blueN": false, "bookfold": false, "bookfoldrev": false,
+	"bookfoldsheetsN": false, "box": false, "brdrartN": false, "brdrb": false, "brdrbar": false,
+	"brdrbtw": false, "brdrcfN": false, "brdrdash": false, "brdrdashd": false, "brdrdashdd": false,
+	"brdrdashdot": false, "brdrdashdotdot": false, "brdrdashdotstr": false, "brdrdashsm": false,
+	"brdrdb": false, "brdrdot": false, "brdremboss": false, "brdrengrave": false, "brdrframe": false,
+	"brdrhair": false, "brdrinset": false, "brdrl": false, "brdrnil": false, "brdrnone": false,
+	"brdroutset": false, "brdrr": false, "brdrs": false, "brdrsh": false, "brdrt": false,
+	"brdrtbl": false, "brdrth": false, "brdrthtnlg": false, "brdrthtnmg": false, "brdrthtnsg": false,
+	"brdrtnthlg": false, "brdrtnthmg": false, "brdrtnthsg": false, "brdrtnthtnlg": false,
+	"brdrtnthtnmg": false, "brdrtnthtnsg": false, "brdrtriple": false, "brdrwN": false,
+	"brdrwavy": false, "brdrwavydb": false, "brkfrm": false, "brspN": false, "bullet": false,
+	"buptim": true, "bxe": false, "caccentfive": false, "caccentfour": false, "caccentone": false,
+	"caccentsix": false, "caccentthree": false, "caccenttwo": false, "cachedcolbal": false,
+	"caps": false, "category": true, "cbN": false, "cbackgroundone": false, "cbackgroundtwo": false,
+	"cbpatN": false, "cchsN": false, "cell": false, "cellxN": false, "cfN": false,
+	"cfollowedhyperlink": false, "cfpatN": false, "cgridN": false, "charrsidN": false,
+	"charscalexN": false, "chatn": false, "chbgbdiag": false, "chbgcross": false, "chbgdcross": false,
+	"chbgdkbdiag": false, "chbgdkcross": false, "chbgdkdcross": false, "chbgdkfdiag": false,
+	"chbgdkhoriz": false, "chbgdkvert": false, "chbgfdiag": false, "chbghoriz": false,
+	"chbgvert": false, "chbrdr": false, "chcbpatN": false, "chcfpatN": false, "chdate": false,
+	"chdpa": false, "chdpl": false, "chftn": false, "chftnsep": false, "chftnsepc": false,
+	"chhresN": false, "chpgn": false, "chshdngN": false, "chtime": false, "chyperlink": false,
+	"clFitText": false, "clNoWrap": false, "clbgbdiag": false, "clbgcross": false, "clbgdcross": false,
+	"clbgdkbdiag": false, "clbgdkcross": false, "clbgdkdcross": false, "clbgdkfdiag": false,
+	"clbgdkhor": false, "clbgdkvert": false, "clbgfdiag": false, "clbghoriz": false, "clbgvert": false,
+	"clbrdrb": false, "clbrdrl": false, "clbrdrr": false, "clbrdrt": false, "clcbpatN": false,
+	"clcbpatrawN": false, "clcfpatN": false, "clcfpatrawN": false, "cldelN": false,
+	"cldelauthN": false, "cldeldttmN": false, "cldgll": false, "cldglu": false, "clftsWidthN": false,
+	"clhidemark": false, "clins": false, "clinsauthN": false, "clinsdttmN": false, "clmgf": false,
+	"clmrg": false, "clmrgd": false, "clmrgdauthN": false, "clmrgddttmN": false, "clmrgdr": false,
+	"clpadbN": false, "clpadfbN": false, "clpadflN": false, "clpadfrN": false, "clpadftN": false,
+	"clpadlN": false, "clpadrN": false, "clpadtN": false, "clshdngN": false, "clshdngrawN": false,
+	"clshdrawnil": false, "clspbN": false, "clspfbN": false, "clspflN": false, "clspfrN": false,
+	"clspftN": false, "clsplN": false, "clsplit": false, "clsplitr": false, "clsprN": false,
+	"clsptN": false, "cltxbtlr": false, "cltxlrtb": false, "cltxlrtbv": false, "cltxtbrl": false,
+	"cltxtbrlv": false, "clvertalb": false, "clvertalc": false, "clvertalt": false, "clvmgf": false,
+	"clvmrg": false, "clwWidthN": false, "cmaindarkone": false, "cmaindarktwo": false,
+	"cmainlightone": false, "cmainlighttwo": false, "collapsed": false, "colnoN": false,
+	"colorschememapping": true, "colortbl": true, "colsN": false, "colsrN": false, "colsxN": false,
+	"column": false, "colwN": false, "comment": true, "company": true, "contextualspace": false,
+	"cpgN": false, "crauthN": false, "crdateN": false, "creatim": true, "csN": false, "cshadeN": false,
+	"ctextone": false, "ctexttwo": false, "ctintN": false, "ctrl": false, "ctsN": false,
+	"cufiN": false, "culiN": false, "curiN": false, "cvmme": false, "datafield": true,
+	"datastore": true, "date": false, "dbch": false, "defchp": true, "deffN": false,
+	"defformat": false, "deflangN": false, "deflangfeN": false, "defpap": true, "defshp": false,
+	"deftabN": false, "deleted": false, "delrsidN": false, "dfrauthN": false, "dfrdateN": false,
+	"dfrmtxtxN": false, "dfrmtxtyN": false, "dfrstart": false, "dfrstop": false, "dfrxst": false,
+	"dghoriginN": false, "dghshowN": false, "dghspaceN": false, "dgmargin": false, "dgsnap": false,
+	"dgvoriginN": false, "dgvshowN": false, "dgvspaceN": false, "dibitmapN": false, "disabled": false,
+	"dnN": false, "dntblnsbdb": false, "do": true, "dobxcolumn": false, "dobxmargin": false,
+	"dobxpage": false, "dobymargin": false, "dobypage": false, "dobypara": false, "doccomm": true,
+	"doctemp": false, "doctypeN": false, "docvar": true, "dodhgtN": false, "dolock": false,
+	"donotembedlingdataN": false, "donotembedsysfontN": false, "donotshowcomments": false,
+	"donotshowinsdel": false, "donotshowmarkup": false, "donotshowprops": false, "dpaendhol": false,
+	"dpaendlN": false, "dpaendsol": false, "dpaendwN": false, "dparc": false, "dparcflipx": false,
+	"dparcflipy": false, "dpastarthol": false, "dpastartlN": false, "dpastartsol": false,
+	"dpastartwN": false, "dpcallout": false, "dpcoaN": false, "dpcoaccent": false,
+	"dpcobestfit": false, "dpcoborder": false, "dpcodabs": false, "dpcodbottom": false,
+	"dpcodcenter": false, "dpcodescentN": false, "dpcodtop": false, "dpcolengthN": false,
+	"dpcominusx": false, "dpcominusy": false, "dpcooffsetN": false, "dpcosmarta": false,
+	"dpcotdouble": false, "dpcotright": false, "dpcotsingle": false, "dpcottriple": false,
+	"dpcountN": false, "dpellipse": false, "dpendgroup": false, "dpfillbgcbN": false,
+	"dpfillbgcgN": false, "dpfillbgcrN": false, "dpfillbggrayN": false, "dpfillbgpal": false,
+	"dpfillfgcbN": false, "dpfillfgcgN": false, "dpfillfgcrN": false, "dpfillfggrayN": false,
+	"dpfillfgpal": false, "dpfillpatN": false, "dpgroup": false, "dpline": false, "dplinecobN": false,
+	"dplinecogN": false, "dplinecorN": false, "dplinedado": false, "dplinedadodo": false,
+	"dplinedash": false, "dplinedot": false, "dplinegrayN": false, "dplinehollow": false,
+	"dplinepal": false, "dplinesolid": false, "dplinewN": false, "dppolycountN": false,
+	"dppolygon": false, "dppolyline": false, "dpptxN": false, "dpptyN": false, "dprect": false,
+	"dproundr": false, "dpshadow": false, "dpshadxN": false, "dpshadyN": false, "dptxbtlr": false,
+	"dptxbx": false, "dptxbxmarN": false, "dptxbxtext": true, "dptxlrtb": false, "dptxlrtbv": false,
+	"dptxtbrl": false, "dptxtbrlv": false, "dpxN": false, "dpxsizeN": false, "dpyN": false,
+	"dpysizeN": false, "dropcapliN": false, "dropcaptN": false, "dsN": false, "dxfrtextN": false,
+	"dyN": false, "ebcend": true, "ebcstart": true, "edminsN": false, "embo": false, "emdash": false,
+	"emfblip": false, "emspace": false, "endash": false, "enddoc": false, "endnhere": false,
+	"endnotes": false, "enforceprotN": false, "enspace": false, "expndN": false, "expndtwN": false,
+	"expshrtn": false, "fN": false, "faauto": false, "facenter": false, "facingp": false,
+	"factoidname": true, "fafixed": false, "fahang": false, "falt": true, "faroman": false,
+	"favar": false, "fbiasN": false, "fbidi": false, "fbidis": false, "fbimajor": false,
+	"fbiminor": false, "fchars": true, "fcharsetN": false, "fcsN": false, "fdbmajor": false,
+	"fdbminor": false, "fdecor": false, "felnbrelev": false, "fetN": false, "fetch": false,
+	"ffdefresN": false, "ffdeftext": true, "ffentrymcr": true, "ffexitmcr": true, "ffformat": true,
+	"ffhaslistboxN": false, "ffhelptext": true, "ffhpsN": false, "ffl": true, "ffmaxlenN": false,
+	"ffname": true, "ffownhelpN": false, "ffownstatN": false, "ffprotN": false, "ffrecalcN": false,
+	"ffresN": false, "ffsizeN": false, "ffstattext": true, "fftypeN": false, "fftypetxtN": false,
+	"fhimajor": false, "fhiminor": false, "fiN": false, "fidN": false, "field": true, "file": true,
+	"filetbl": true, "fittextN": false, "fjgothic": false, "fjminchou": false, "fldalt": false,
+	"flddirty": false, "fldedit": false, "fldinst": true, "fldlock": false, "fldpriv": false,
+	"fldrslt": true, "fldtype": true, "flomajor": false, "flominor": false, "fmodern": false,
+	"fnN": false, "fname": true, "fnetwork": false, "fnil": false, "fnonfilesys": false,
+	"fontemb": true, "fontfile": true, "fonttbl": true, "footer": true, "footerf": true,
+	"footerl": true, "footerr": true, "footeryN": false, "footnote": true, "forceupgrade": false,
+	"formdisp": false, "formfield": true, "formprot": false, "formshade": false, "fosnumN": false,
+	"fprqN": false, "fracwidth": false, "frelativeN": false, "frmtxbtlr": false, "frmtxlrtb": false,
+	"frmtxlrtbv": false, "frmtxtbrl": false, "frmtxtbrlv": false, "froman": false, "fromhtmlN": false,
+	"fromtext": false, "fsN": false, "fscript": false, "fswiss": false, "ftech": false,
+	"ftnalt": false, "ftnbj": false, "ftncn": true, "ftnil": false, "ftnlytwnine": false,
+	"ftnnalc": false, "ftnnar": false, "ftnnauc": false, "ftnnchi": false, "ftnnchosung": false,
+	"ftnncnum": false, "ftnndbar": false, "ftnndbnum": false, "ftnndbnumd": false, "ftnndbnumk": false,
+	"ftnndbnumt": false, "ftnnganada": false, "ftnngbnum": false, "ftnngbnumd": false,
+	"ftnngbnumk": false, "ftnngbnuml": false, "ftnnrlc": false, "ftnnruc": false, "ftnnzodiac": false,
+	"ftnnzodiacd": false, "ftnnzodiacl": false, "ftnrestart": false, "ftnrstcont": false,
+	"ftnrstpg": false, "ftnsep": true, "ftnsepc": true, "ftnstartN": false, "ftntj": false,
+	"fttruetype": false, "fvaliddos": false, "fvalidhpfs": false, "fvalidmac": false,
+	"fvalidntfs": false, "g": true, "gcwN": false, "generator": true, "greenN": false,
+	"grfdoceventsN": false, "gridtbl": true, "gutterN": false, "gutterprl": false, "guttersxnN": false,
+	"header": true, "headerf": true, "headerl": true, "headerr": true, "headeryN": false,
+	"hich": false, "highlightN": false, "hl": true, "hlfr": true, "hlinkbase": true, "hlloc": true,
+	"hlsrc": true, "horzdoc": false, "horzsect": false, "horzvertN": false, "hrN": false,
+	"hresN": false, "hrule": false, "hsv": true, "htmautsp": false, "htmlbase": false,
+	"htmlrtf": false, "htmltag": true, "hwelevN": false, "hyphauto": false, "hyphcaps": false,
+	"hyphconsecN": false, "hyphhotzN": false, "hyphpar": false, "i": false, "idN": false,
+	"ignoremixedcontentN": false, "ilfomacatclnupN": false, "ilvlN": false, "impr": false,
+	"indmirror": false, "indrlsweleven": false, "info": true, "insrsidN": false, "intbl": false,
+	"ipgpN": false, "irowN": false, "irowbandN": false, "itapN": false, "ixe": false,
+	"jclisttab": false, "jcompress": false, "jexpand": false, "jis": false, "jpegblip": false,
+	"jsksu": false, "keep": false, "keepn": false, "kerningN": false, "keycode": true,
+	"keywords": true, "krnprsnet": false, "ksulangN": false, "landscape": false, "langN": false,
+	"langfeN": false, "langfenpN": false, "langnpN": false, "lastrow": false, "latentstyles": true,
+	"lbrN": false, "lchars": true, "ldblquote": false, "levelN": false, "levelfollowN": false,
+	"levelindentN": false, "leveljcN": false, "leveljcnN": false, "levellegalN": false,
+	"levelnfcN": false, "levelnfcnN": false, "levelnorestartN": false, "levelnumbers": true,
+	"leveloldN": false, "levelpictureN": false, "levelpicturenosize": false, "levelprevN": false,
+	"levelprevspaceN": false, "levelspaceN": false, "levelstartatN": false, "leveltemplateidN": false,
+	"leveltext": true, "lfolevel": true, "liN": false, "linN": false, "line": false,
+	"linebetcol": false, "linecont": false, "linemodN": false, "lineppage": false,
+	"linerestart": false, "linestartN": false, "linestartsN": false, "linexN": false,
+	"linkself": false, "linkstyles": false, "linkval": true, "lisaN": false, "lisbN": false,
+	"list": true, "listhybrid": false, "listidN": false, "listlevel": true, "listname": true,
+	"listoverride": true, "listoverridecountN": false, "listoverrideformatN": false,
+	"listoverridestartat": false, "listoverridetable": true, "listpicture": true,
+	"listrestarthdnN": false, "listsimpleN": false, "liststyleidN": false, "liststylename": true,
+	"listtable": true, "listtemplateidN": false, "listtext": true, "lnbrkrule": false,
+	"lndscpsxn": false, "lnongrid": false, "loch": false, "lquote": false, "lsN": false,
+	"lsdlockedN": false, "lsdlockeddefN": false, "lsdlockedexcept": true, "lsdpriorityN": false,
+	"lsdprioritydefN": false, "lsdqformatN": false, "lsdqformatdefN": false, "lsdsemihiddenN": false,
+	"lsdsemihiddendefN": false, "lsdstimaxN": false, "lsdunhideusedN": false,
+	"lsdunhideuseddefN": false, "ltrch": false, "ltrdoc": false, "ltrmark": false, "ltrpar": false,
+	"ltrrow": false, "ltrsect": false, "lvltentative": false, "lytcalctblwd": false,
+	"lytexcttp": false, "lytprtmet": false, "lyttblrtgr": false, "mac": false, "macc": true,
+	"maccPr": true, "macpict": false, "mailmerge": true, "makebackup": false, "maln": true,
+	"malnScr": true, "manager": true, "margPr": true, "margSzN": false, "margbN": false,
+	"margbsxnN": false, "marglN": false, "marglsxnN": false, "margmirror": false, "margmirsxn": false,
+	"margrN": false, "margrsxnN": false, "margtN": false, "margtsxnN": false, "mbar": true,
+	"mbarPr": true, "mbaseJc": true, "mbegChr": true, "mborderBox": true, "mborderBoxPr": true,
+	"mbox": true, "mboxPr": true, "mbrkBinN": false, "mbrkBinSubN": false, "mbrkN": false,
+	"mcGpN": false, "mcGpRuleN": false, "mcSpN": false, "mchr": true, "mcount": true, "mctrlPr": true,
+	"md": true, "mdPr": true, "mdefJcN": false, "mdeg": true, "mdegHide": true, "mden": true,
+	"mdiff": true, "mdiffStyN": false, "mdispdefN": false, "me": true, "mendChr": true, "meqArr": true,
+	"meqArrPr": true, "mf": true, "mfName": true, "mfPr": true, "mfunc": true, "mfuncPr": true,
+	"mgroupChr": true, "mgroupChrPr": true, "mgrow": true, "mhideBot": true, "mhideLeft": true,
+	"mhideRight": true, "mhideTop": true, "mhtmltag": true, "minN": false, "mintLimN": false,
+	"minterSpN": false, "mintraSpN": false, "mjcN": false, "mlMarginN": false, "mlim": true,
+	"mlimloc": true, "mlimlow": true, "mlimlowPr": true, "mlimupp": true, "mlimuppPr": true,
+	"mlit": false, "mm": true, "mmPr": true, "mmaddfieldname": true, "mmath": true,
+	"mmathFontN": false, "mmathPict": true, "mmathPr": true, "mmattach": false, "mmaxdist": true,
+	"mmblanklines": false, "mmc": true, "mmcJc": true, "mmcPr": true, "mmconnectstr": true,
+	"mmconnectstrdata": true, "mmcs": true, "mmdatasource": true, "mmdatatypeaccess": false,
+	"mmdatatypeexcel": false, "mmdatatypefile": false, "mmdatatypeodbc": false,
+	"mmdatatypeodso": false, "mmdatatypeqt": false, "mmdefaultsql": false, "mmdestemail": false,
+	"mmdestfax": false, "mmdestnewdocN": false, "mmdestprinter": false, "mmerrorsN": false,
+	"mmfttypeaddress": false, "mmfttypebarcode": false, "mmfttypedbcolumn": false,
+	"mmfttypemapped": false, "mmfttypenull": false, "mmfttypesalutation": false,
+	"mmheadersource": true, "mmjdsotypeN": false, "mmlinktoquery": false, "mmmailsubject": true,
+	"mmmaintypecatalog": false, "mmmaintypeemail": false, "mmmaintypeenvelopes": false,
+	"mmmaintypefax": false, "mmmaintypelabels": false, "mmmaintypeletters": false, "mmodso": true,
+	"mmodsoactiveN": false, "mmodsocoldelimN": false, "mmodsocolumnN": false, "mmodsodynaddrN": false,
+	"mmodsofhdrN": false, "mmodsofilter": true, "mmodsofldmpdata": true, "mmodsofmcolumnN": false,
+	"mmodsohashN": false, "mmodsolidN": false, "mmodsomappedname": true, "mmodsoname": true,
+	"mmodsorecipdata": true, "mmodsosort": true, "mmodsosrc": true, "mmodsotable": true,
+	"mmodsoudl": true, "mmodsoudldataN": true, "mmodsouniquetag": true, "mmquery": true, "mmr": true,
+	"mmreccurN": false, "mmshowdata": false, "mnary": true, "mnaryLimN": false, "mnaryPr": true,
+	"mnoBreak": true, "mnor": false, "mnum": true, "moMath": true, "moMathPara": true,
+	"moMathParaPr": true, "moN": false, "mobjDist": true, "mopEmu": true, "mphant": true,
+	"mphantPr": true, "mplcHide": true, "mpos": true, "mpostSpN": false, "mpreSpN": false, "mr": true,
+	"mrMarginN": false, "mrPr": true, "mrSpN": false, "mrSpRuleN": false, "mrad": true, "mradPr": true,
+	"msPre": true, "msPrePr": true, "msSub": true, "msSubPr": true, "msSubSup": true,
+	"msSubSupPr": true, "msSup": true, "msSupPr": true, "mscrN": false, "msepChr": true, "mshow": true,
+	"mshp": true, "msmallFracN": false, "msmcap": false, "mstrikeBLTR": true, "mstrikeH": true,
+	"mstrikeTLBR": true, "mstrikeV": true, "mstyN": false, "msub": true, "msubHide": true,
+	"msup": true, "msupHide": true, "mtransp": true, "mtype": true, "muser": false, "mvauthN": false,
+	"mvdateN": false, "mvertJc": true, "mvf": false, "mvfmf": true, "mvfml": true, "mvt": false,
+	"mvtof": true, "mvtol": true, "mwrapIndentN": false, "mwrapRightN": false, "mzeroAsc": true,
+	"mzeroDesc": true, "mzeroWid": true, "nestcell": false, "nestrow": false, "nesttableprops": true,
+	"newtblstyruls": false, "nextfile": true, "noafcnsttbl": false, "nobrkwrptbl": false,
+	"nocolbal": false, "nocompatoptions": false, "nocwrap": false, "nocxsptable": false,
+	"noextrasprl": false, "nofcharsN": false, "nofcharswsN": false, "nofeaturethrottle": false,
+	"nofpagesN": false, "nofwordsN": false, "nogrowautofit": false, "noindnmbrts": false,
+	"nojkernpunct": false, "nolead": false, "noline": false, "nolnhtadjtbl": false,
+	"nonesttables": true, "nonshppict": false, "nooverflow": false, "noproof": false,
+	"noqfpromote": false, "nosectexpand": false, "nosnaplinegrid": false, "nospaceforul": false,
+	"nosupersub": false, "notabind": false, "notbrkcnstfrctbl": false, "notcvasp": false,
+	"notvatxbx": false, "nouicompat": false, "noultrlspc": false, "nowidctlpar": false,
+	"nowrap": false, "nowwrap": false, "noxlattoyen": false, "objalias": true, "objalignN": false,
+	"objattph": false, "objautlink": false, "objclass": true, "objcropbN": false, "objcroplN": false,
+	"objcroprN": false, "objcroptN": false, "objdata": true, "object": true, "objemb": false,
+	"objhN": false, "objhtml": false, "objicemb": false, "objlink": false, "objlock": false,
+	"objname": true, "objocx": false, "objpub": false, "objscalexN": false, "objscaleyN": false,
+	"objsect": true, "objsetsize": false, "objsub": false, "objtime": true, "objtransyN": false,
+	"objupdate": false, "objwN": false, "ogutterN": false, "oldas": false, "oldcprops": true,
+	"oldlinewrap": false, "oldpprops": true, "oldsprops": true, "oldtprops": true, "oleclsid": true,
+	"operator": true, "otblrul": false, "outl": false, "outlinelevelN": false, "overlay": false,
+	"page": false, "pagebb": false, "panose": true, "paperhN": false, "paperwN": false, "par": false,
+	"pararsidN": false, "pard": false, "password": true, "passwordhash": true, "pc": false,
+	"pca": false, "pgbrdrb": false, "pgbrdrfoot": false, "pgbrdrhead": false, "pgbrdrl": false,
+	"pgbrdroptN": false, "pgbrdrr": false, "pgbrdrsnap": false, "pgbrdrt": false, "pghsxnN": false,
+	"pgnbidia": false, "pgnbidib": false, "pgnchosung": false, "pgncnum": false, "pgncont": false,
+	"pgndbnum": false, "pgndbnumd": false, "pgndbnumk": false, "pgndbnumt": false, "pgndec": false,
+	"pgndecd": false, "pgnganada": false, "pgngbnum": false, "pgngbnumd": false, "pgngbnumk": false,
+	"pgngbnuml": false, "pgnhindia": false, "pgnhindib": false, "pgnhindic": false, "pgnhindid": false,
+	"pgnhnN": false, "pgnhnsc": false, "pgnhnsh": false, "pgnhnsm": false, "pgnhnsn": false,
+	"pgnhnsp": false, "pgnid": false, "pgnlcltr": false, "pgnlcrm": false, "pgnrestart": false,
+	"pgnstartN": false, "pgnstartsN": false, "pgnthaia": false, "pgnthaib": false, "pgnthaic": false,
+	"pgnucltr": false, "pgnucrm": false, "pgnvieta": false, "pgnxN": false, "pgnyN": false,
+	"pgnzodiac": false, "pgnzodiacd": false, "pgnzodiacl": false, "pgp": true, "pgptbl": true,
+	"pgwsxnN": false, "phcol": false, "phmrg": false, "phpg": false, "picbmp": false, "picbppN": false,
+	"piccropbN": false, "piccroplN": false, "piccroprN": false, "piccroptN": false, "pichN": false,
+	"pichgoalN": false, "picprop": true, "picscaled": false, "picscalexN": false, "picscaleyN": false,
+	"pict": true, "picwN": false, "picwgoalN": false, "pindtabqc": false, "pindtabql": false,
+	"pindtabqr": false, "plain": false, "pmartabqc": false, "pmartabql": false, "pmartabqr": false,
+	"pmmetafileN": false, "pn": true, "pnacross": false, "pnaiu": false, "pnaiud": false,
+	"pnaiueo": false, "pnaiueod": false, "pnb": false, "pnbidia": false, "pnbidib": false,
+	"pncaps": false, "pncard": false, "pncfN": false, "pnchosung": false, "pncnum": false,
+	"pndbnum": false, "pndbnumd": false, "pndbnumk": false, "pndbnuml": false, "pndbnumt": false,
+	"pndec": false, "pndecd": false, "pnfN": false, "pnfsN": false, "pnganada": false,
+	"pngblip": false, "pngbnum": false, "pngbnumd": false, "pngbnumk": false, "pngbnuml": false,
+	"pnhang": false, "pni": false, "pnindentN": false, "pniroha": false, "pnirohad": false,
+	"pnlcltr": false, "pnlcrm": false, "pnlvlN": false, "pnlvlblt": false, "pnlvlbody": false,
+	"pnlvlcont": false, "pnnumonce": false, "pnord": false, "pnordt": false, "pnprev": false,
+	"pnqc": false, "pnql": false, "pnqr": false, "pnrauthN": false, "pnrdateN": false,
+	"pnrestart": false, "pnrnfcN": false, "pnrnot": false, "pnrpnbrN": false, "pnrrgbN": false,
+	"pnrstartN": false, "pnrstopN": false, "pnrxstN": false, "pnscaps": false, "pnseclvlN": true,
+	"pnspN": false, "pnstartN": false, "pnstrike": false, "pntext": true, "pntxta": true,
+	"pntxtb": true, "pnucltr": false, "pnucrm": false, "pnul": false, "pnuld": false,
+	"pnuldash": false, "pnuldashd": false, "pnuldashdd": false, "pnuldb": false, "pnulhair": false,
+	"pnulnone": false, "pnulth": false, "pnulw": false, "pnulwave": false, "pnzodiac": false,
+	"pnzodiacd": false, "pnzodiacl": false, "posnegxN": false, "posnegyN": false, "posxN": false,
+	"posxc": false, "posxi": false, "posxl": false, "posxo": false, "posxr": false, "posyN": false,
+	"posyb": false, "posyc": false, "posyil": false, "posyin": false, "posyout": false, "posyt": false,
+	"prauthN": false, "prcolbl": false, "prdateN": false, "printdata": false, "printim": true,
+	"private": true, "propname": true, "proptypeN": false, "protect": false, "protend": true,
+	"protlevelN": false, "protstart": true, "protusertbl": true, "psover": false, "pszN": false,
+	"ptabldot": false, "ptablmdot": false, "ptablminus": false, "ptablnone": false,
+	"ptabluscore": false, "pubauto": false, "pvmrg": false, "pvpara": false, "pvpg": false,
+	"pwdN": false, "pxe": true, "qc": false, "qd": false, "qj": false, "qkN": false, "ql": false,
+	"qmspace": false, "qr": false, "qt": false, "rawclbgbdiag": false, "rawclbgcross": false,
+	"rawclbgdcross": false, "rawclbgdkbdiag": false, "rawclbgdkcross": false, "rawclbgdkdcross": false,
+	"rawclbgdkfdiag": false, "rawclbgdkhor": false, "rawclbgdkvert": false, "rawclbgfdiag": false,
+	"rawclbghoriz": false, "rawclbgvert": false, "rdblquote": false, "readonlyrecommended": false,
+	"readprot": false, "redN": false, "relyonvmlN": false, "remdttm": false, "rempersonalinfo": false,
+	"result": true, "revauthN": false, "revauthdelN": false, "revbarN": false, "revdttmN": false,
+	"revdttmdelN": false, "revised": false, "revisions": false, "revpropN": false, "revprot": false,
+	"revtbl": true, "revtim": true, "riN": false, "rinN": false, "row": false, "rquote": false,
+	"rsidN": false, "rsidrootN": false, "rsidtbl": true, "rsltbmp": false, "rslthtml": false,
+	"rsltmerge": false, "rsltpict": false, "rsltrtf": false, "rslttxt": false, "rtfN": true,
+	"rtlch": false, "rtldoc": false, "rtlgutter": false, "rtlmark": false, "rtlpar": false,
+	"rtlrow": false, "rtlsect": false, "rxe": true, "sN": false, "saN": false, "saautoN": false,
+	"saftnnalc": false, "saftnnar": false, "saftnnauc": false, "saftnnchi": false,
+	"saftnnchosung": false, "saftnncnum": false, "saftnndbar": false, "saftnndbnum": false,
+	"saftnndbnumd": false, "saftnndbnumk": false, "saftnndbnumt": false, "saftnnganada": false,
+	"saftnngbnum": false, "saftnngbnumd": false, "saftnngbnumk": false, "saftnngbnuml": false,
+	"saftnnrlc": false, "saftnnruc": false, "saftnnzodiac": false, "saftnnzodiacd": false,
+	"saftnnzodiacl": false, "saftnrestart": false, "saftnrstcont": false, "saftnstartN": false,
+	"sautoupd": false, "saveinvalidxml": false, "saveprevpict": false, "sbN": false,
+	"sbasedonN": false, "sbautoN": false, "sbkcol": false, "sbkeven": false, "sbknone": false,
+	"sbkodd": false, "sbkpage": false, "sbys": false, "scaps": false, "scompose": false, "secN": false,
+	"sect": false, "sectd": false, "sectdefaultcl": false, "sectexpandN": false,
+	"sectlinegridN": false, "sectnum": false, "sectrsidN": false, "sectspecifycl": false,
+	"sectspecifygenN": false, "sectspecifyl": false, "sectunlocked": false, "sftnbj": false,
+	"sftnnalc": false, "sftnnar": false, "sftnnauc": false, "sftnnchi": false, "sftnnchosung": false,
+	"sftnncnum": false, "sftnndbar": false, "sftnndbnum": false, "sftnndbnumd": false,
+	"sftnndbnumk": false, "sftnndbnumt": false, "sftnnganada": false, "sftnngbnum": false,
+	"sftnngbnumd": false, "sftnngbnumk": false, "sftnngbnuml": false, "sftnnrlc": false,
+	"sftnnruc": false, "sftnnzodiac": false, "sftnnzodiacd": false, "sftnnzodiacl": false,
+	"sftnrestart": false, "sftnrstcont": false, "sftnrstpg": false, "sftnstartN": false,
+	"sftntj": false, "shad": false, "shadingN": false, "shidden": false, "shift": false,
+	"showplaceholdtextN": false, "showxmlerrorsN": false, "shp": true, "shpbottomN": false,
+	"shpbxcolumn": false, "shpbxignore": false, "shpbxmargin": false, "shpbxpage": false,
+	"shpbyignore": false, "shpbymargin": false, "shpbypage": false, "shpbypara": false,
+	"shpfblwtxtN": false, "shpfhdrN": false, "shpgrp": true, "shpinst": true, "shpleftN": false,
+	"shplidN": false, "shplockanchor": false, "shppict": true, "shprightN": false, "shprslt": true,
+	"shptopN": false, "shptxt": true, "shpwrN": false, "shpwrkN": false, "shpzN": false, "slN": false,
+	"slinkN": false, "slmultN": false, "slocked": false, "sn": true, "snaptogridincell": false,
+	"snextN": false, "softcol": false, "softlheightN": false, "softline": false, "softpage": false,
+	"sp": true, "spersonal": false, "spltpgpar": false, "splytwnine": false, "spriorityN": false,
+	"sprsbsp": false, "sprslnsp": false, "sprsspbf": false, "sprstsm": false, "sprstsp": false,
+	"spv": false, "sqformat": false, "srauthN": false, "srdateN": false, "sreply": false,
+	"ssemihiddenN": false, "staticval": true, "stextflowN": false, "strike": false, "strikedN": false,
+	"stshfbiN": false, "stshfdbchN": false, "stshfhichN": false, "stshflochN": false,
+	"stylelock": false, "stylelockbackcomp": false, "stylelockenforced": false,
+	"stylelockqfset": false, "stylelocktheme": false, "stylesheet": true, "stylesortmethodN": false,
+	"styrsidN": false, "sub": false, "subdocumentN": false, "subfontbysize": false, "subject": true,
+	"sunhideusedN": false, "super": false, "sv": true, "svb": true, "swpbdr": false, "tab": false,
+	"tabsnoovrlp": false, "taprtl": false, "tbN": false, "tblindN": false, "tblindtypeN": false,
+	"tbllkbestfit": false, "tbllkborder": false, "tbllkcolor": false, "tbllkfont": false,
+	"tbllkhdrcols": false, "tbllkhdrrows": false, "tbllklastcol": false, "tbllklastrow": false,
+	"tbllknocolband": false, "tbllknorowband": false, "tbllkshading": false, "tblrsidN": false,
+	"tc": true, "tcelld": false, "tcfN": false, "tclN": false, "tcn": false, "tdfrmtxtBottomN": false,
+	"tdfrmtxtLeftN": false, "tdfrmtxtRightN": false, "tdfrmtxtTopN": false, "template": true,
+	"themedata": true, "themelangN": false, "themelangcsN": false, "themelangfeN": false,
+	"time": false, "title": true, "titlepg": false, "tldot": false, "tleq": false, "tlhyph": false,
+	"tlmdot": false, "tlth": false, "tlul": false, "toplinepunct": false, "tphcol": false,
+	"tphmrg": false, "tphpg": false, "tposnegxN": false, "tposnegyN": false, "tposxN": false,
+	"tposxc": false, "tposxi": false, "tposxl": false, "tposxo": false, "tposxr": false,
+	"tposyN": false, "tposyb": false, "tposyc": false, "tposyil": false, "tposyin": false,
+	"tposyout": false, "tposyt": false, "tpvmrg": false, "tpvpara": false, "tpvpg": false,
+	"tqc": false, "tqdec": false, "tqr": false, "trackformattingN": false, "trackmovesN": false,
+	"transmf": false, "trauthN": false, "trautofitN": false, "trbgbdiag": false, "trbgcross": false,
+	"trbgdcross": false, "trbgdkbdiag": false, "trbgdkcross": false, "trbgdkdcross": false,
+	"trbgdkfdiag": false, "trbgdkhor": false, "trbgdkvert": false, "trbgfdiag": false,
+	"trbghoriz": false, "trbgvert": false, "trbrdrb": false, "trbrdrh": false, "trbrdrl": false,
+	"trbrdrr": false, "trbrdrt": false, "trbrdrv": false, "trcbpatN": false, "trcfpatN": false,
+	"trdateN": false, "trftsWidthAN": false, "trftsWidthBN": false, "trftsWidthN": false,
+	"trgaphN": false, "trhdr": false, "trkeep": false, "trkeepfollow": false, "trleftN": false,
+	"trowd": false, "trpaddbN": false, "trpaddfbN": false, "trpaddflN": false, "trpaddfrN": false,
+	"trpaddftN": false, "trpaddlN": false, "trpaddrN": false, "trpaddtN": false, "trpadobN": false,
+	"trpadofbN": false, "trpadoflN": false, "trpadofrN": false, "trpadoftN": false, "trpadolN": false,
+	"trpadorN": false, "trpadotN": false, "trpatN": false, "trqc": false, "trql": false, "trqr": false,
+	"trrhN": false, "trshdngN": false, "trspdbN": false, "trspdfbN": false, "trspdflN": false,
+	"trspdfrN": false, "trspdftN": false, "trspdlN": false, "trspdrN": false, "trspdtN": false,
+	"trspobN": false, "trspofbN": false, "trspoflN": false, "trspofrN": false, "trspoftN": false,
+	"trspolN": false, "trsporN": false, "trspotN": false, "truncatefontheight": false,
+	"truncex": false, "trwWidthAN": false, "trwWidthBN": false, "trwWidthN": false, "tsN": false,
+	"tsbgbdiag": false, "tsbgcross": false, "tsbgdcross": false, "tsbgdkbdiag": false,
+	"tsbgdkcross": false, "tsbgdkdcross": false, "tsbgdkfdiag": false, "tsbgdkhor": false,
+	"tsbgdkvert": false, "tsbgfdiag": false, "tsbghoriz": false, "tsbgvert": false, "tsbrdrb": false,
+	"tsbrdrdgl": false, "tsbrdrdgr": false, "tsbrdrh": false, "tsbrdrl": false, "tsbrdrr": false,
+	"tsbrdrt": false, "tsbrdrv": false, "tscbandhorzeven": false, "tscbandhorzodd": false,
+	"tscbandshN": false, "tscbandsvN": false, "tscbandverteven": false, "tscbandvertodd": false,
+	"tscellcbpatN": false, "tscellcfpatN": false, "tscellpaddbN": false, "tscellpaddfbN": false,
+	"tscellpaddflN": false, "tscellpaddfrN": false, "tscellpaddftN": false, "tscellpaddlN": false,
+	"tscellpaddrN": false, "tscellpaddtN": false, "tscellpctN": false, "tscellwidthN": false,
+	"tscellwidthftsN": false, "tscfirstcol": false, "tscfirstrow": false, "tsclastcol": false,
+	"tsclastrow": false, "tscnecell": false, "tscnwcell": false, "tscsecell": false,
+	"tscswcell": false, "tsd": false, "tsnowrap": false, "tsrowd": false, "tsvertalb": false,
+	"tsvertalc": false, "tsvertalt": false, "twoinoneN": false, "twoonone": false, "txN": false,
+	"txbxtwalways": false, "txbxtwfirst": false, "txbxtwfirstlast": false, "txbxtwlast": false,
+	"txbxtwno": false, "txe": true, "uN": false, "ucN": false, "ud": true, "ul": false, "ulcN": false,
+	"uld": false, "uldash": false, "uldashd": false, "uldashdd": false, "uldb": false, "ulhair": false,
+	"ulhwave": false, "ulldash": false, "ulnone": false, "ulth": false, "ulthd": false,
+	"ulthdash": false, "ulthdashd": false, "ulthdashdd": false, "ulthldash": false,
+	"ululdbwave": false, "ulw": false, "ulwave": false, "upN": false, "upr": true, "urtfN": false,
+	"useltbaln": false, "usenormstyforlist": false, "userprops": true, "usexform": false,
+	"utinl": false, "v": false, "validatexmlN": false, "vernN": false, "versionN": false,
+	"vertal": false, "vertalb": false, "vertalc": false, "vertalj": false, "vertalt": false,
+	"vertdoc": false, "vertsect": false, "viewbkspN": false, "viewkindN": false, "viewnobound": false,
+	"viewscaleN": false, "viewzkN": false, "wbitmapN": false, "wbmbitspixelN": false,
+	"wbmplanesN": false, "wbmwidthbyteN": false, "webhidden": false, "wgrffmtfilter": true,
+	"widctlpar": false, "widowctrl": false, "windowcaption": true, "wmetafileN": false, "wpeqn": false,
+	"wpjst": false, "wpsp": false, "wraparound": false, "wrapdefault": false, "wrapthrough": false,
+	"wraptight": false, "wraptrsp": false, "writereservation": true, "writereservhash": true,
+	"wrppunct": false, "xe": true, "xefN": false, "xform": true, "xmlattr": false, "xmlattrname": true,
+	"xmlattrnsN": false, "xmlattrvalue": true, "xmlclose": true, "xmlname": true, "xmlnsN": false,
+	"xmlnstbl": true, "xmlopen": true, "xmlsdttcell": false, "xmlsdttpara": false,
+	"xmlsdttregular": false, "xmlsdttrow": false, "xmlsdttunknown": false, "yrN": false, "ytsN": false,
+	"yxe": false, "zwbo": false, "zwj": false, "zwnbo": false, "zwnj": false,
+}
+
+// knownControl returns whether control is in the RTF specification and
+// whether it is a destination. Any control word can have a parameter
+func knownControl(control string) (bool, bool) {
+	if dest, ok := knownControls[control]; ok {
+		return true, dest
+	}
+	if strings.HasSuffix(control, "N") {
+		dest, ok := knownControls[control[:len(control)-1]]
+		return ok, dest
+	}
+	return false, false
+}
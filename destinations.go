@@ -0,0 +1,117 @@
+package rtf2txt
+
+// destinationUse is the set of ways a destination is handled
+type destinationUse int
+
+const (
+	useExtended destinationUse = 1 << iota // understood by the converter after \*
+	useStarred                             // only allowed after \* by the RTF specification and not written without it by Word
+	useCaptured                            // text kept by the converter, even inside the list tables whose text is ignored
+	useText                                // text is part of the document
+	useRedacted                            // text searched by Redact besides the text of the document
+	usePersonal                            // removed by Sanitize as it identifies people, computers or revisions
+	useData                                // text is hex encoded data which can contain text of its own
+)
+
+// destinationUses are the destinations handled by name and how each is
+// handled. The lists of destinations of the converter, Format, Lint, Redact,
+// Replace and Sanitize are taken from it
+var destinationUses = map[string]destinationUse{
+	"annotation":         useExtended | useStarred | useCaptured | useRedacted,
+	"atnauthor":          useExtended | useStarred | useCaptured | useRedacted | usePersonal,
+	"atndate":            useExtended | useStarred | useCaptured,
+	"atnicn":             useStarred,
+	"atnid":              useExtended | useStarred | useCaptured | useRedacted | usePersonal,
+	"atnparent":          useStarred,
+	"atnref":             useExtended | useStarred | useCaptured,
+	"atntime":            useStarred,
+	"atrfend":            useExtended | useStarred | useCaptured,
+	"atrfstart":          useExtended | useStarred | useCaptured,
+	"author":             useRedacted | usePersonal,
+	"bkmkend":            useExtended | useStarred | useCaptured,
+	"bkmkstart":          useExtended | useStarred | useCaptured,
+	"blipuid":            useStarred,
+	"category":           useRedacted,
+	"colorschememapping": useStarred,
+	"comment":            useRedacted,
+	"company":            useRedacted | usePersonal,
+	"datastore":          useStarred | usePersonal | useData,
+	"defchp":             useStarred,
+	"defpap":             useStarred,
+	"doccomm":            useRedacted,
+	"docvar":             useStarred | usePersonal,
+	"falt":               useStarred,
+	"field":              useText,
+	"fldinst":            useStarred | useRedacted,
+	"fldrslt":            useText,
+	"fontemb":            useStarred,
+	"fontfile":           useStarred,
+	"footer":             useText,
+	"footerf":            useText,
+	"footerl":            useText,
+	"footerr":            useText,
+	"footnote":           useText,
+	"generator":          useStarred | usePersonal,
+	"header":             useText,
+	"headerf":            useText,
+	"headerl":            useText,
+	"headerr":            useText,
+	"hlinkbase":          useRedacted,
+	"htmltag":            useStarred,
+	"info":               useRedacted,
+	"keycode":            useStarred,
+	"keywords":           useRedacted,
+	"latentstyles":       useStarred,
+	"leveltext":          useCaptured,
+	"listoverridetable":  useExtended | useStarred,
+	"listpicture":        useStarred,
+	"listtable":          useExtended | useStarred,
+	"listtext":           useCaptured,
+	"manager":            useRedacted | usePersonal,
+	"mhtmltag":           useStarred,
+	"objalias":           useStarred,
+	"objclass":           useExtended | useStarred | useCaptured,
+	"objdata":            useExtended | useStarred | useCaptured | useData,
+	"object":             useCaptured,
+	"objname":            useStarred,
+	"objsect":            useStarred,
+	"oleclsid":           useStarred,
+	"operator":           useRedacted | usePersonal,
+	"panose":             useStarred,
+	"password":           useStarred,
+	"passwordhash":       useStarred,
+	"pgptbl":             useStarred,
+	"picprop":            useStarred,
+	"pict":               useCaptured,
+	"pnseclvlN":          useStarred,
+	"pntext":             useCaptured,
+	"protusertbl":        useStarred,
+	"result":             useCaptured | useRedacted,
+	"revtbl":             useExtended | useStarred | useCaptured,
+	"rsidtbl":            useStarred | usePersonal,
+	"rtfN":               useText,
+	"shpinst":            useStarred,
+	"shppict":            useExtended | useStarred,
+	"shptxt":             useText,
+	"subject":            useRedacted,
+	"themedata":          useStarred,
+	"title":              useRedacted,
+	"userprops":          useStarred,
+	"wgrffmtfilter":      useStarred,
+	"xmlattrname":        useStarred,
+	"xmlattrvalue":       useStarred,
+	"xmlclose":           useStarred,
+	"xmlnstbl":           useStarred | usePersonal,
+	"xmlopen":            useStarred,
+}
+
+// destinationsUsed returns the destinations which are handled in the way use
+func destinationsUsed(use destinationUse) map[string]bool {
+	used := make(map[string]bool)
+	for dest, uses := range destinationUses {
+		if uses&use != 0 {
+			used[dest] = true
+		}
+	}
+	return used
+}
@@ -0,0 +1,17 @@
+package rtf2txt
+
+import "testing"
+
+func TestDestinationUses(t *testing.T) {
+	for dest, uses := range destinationUses {
+		if known, isDest := knownControl(dest); !known || !isDest && dest != "rtfN" {
+			t.Error("expected a destination of the RTF specification", dest)
+		}
+		if uses&useExtended != 0 && uses&useStarred == 0 {
+			t.Error("expected destination understood after \\* to be starred", dest)
+		}
+	}
+	if used := destinationsUsed(useData); len(used) != 2 || !used["objdata"] || !used["datastore"] {
+		t.Error("expected objdata and datastore", used)
+	}
+}
@@ -0,0 +1,57 @@
+package rtf2txt
+
+import (
+	"fmt"
+)
+
+// DiagnosticKind is the kind of content skipped by the converter
+type DiagnosticKind string
+
+// Kinds of Diagnostic
+const (
+	DiagnosticUnknownControl         DiagnosticKind = "unknown control"         // not in the RTF specification
+	DiagnosticUnbalancedBraces       DiagnosticKind = "unbalanced braces"       // extra closing brace or unclosed groups
+	DiagnosticUnsupportedDestination DiagnosticKind = "unsupported destination" // \* destination that is skipped
+	DiagnosticCodePage               DiagnosticKind = "code page"               // code page that isn't decoded
+	DiagnosticIgnoredPicture         DiagnosticKind = "ignored picture"         // picture that is skipped
+)
+
+// Diagnostic is something the converter skipped without failing
+type Diagnostic struct {
+	Kind    DiagnosticKind
+	Offset  int64  // offset of the RTF data, or -1 if unknown
+	Control string // control word, such as "fonttbl" or "fN", if there is one
+	Message string
+	Path    string // slash separated path of the RTF file in a batch, or ""
+}
+
+// diagnose sends a Diagnostic to Options.Diagnostics
+func (c *converter) diagnose(kind DiagnosticKind, offset int64, control, message string) {
+	if c.opts.Diagnostics != nil {
+		c.opts.Diagnostics(Diagnostic{Kind: kind, Offset: offset, Control: control, Message: message})
+	}
+}
+
+// diagnoseControl reports control words which aren't in the specification
+// and code pages which aren't decoded
+func (c *converter) diagnoseControl(control string, num int) {
+	if c.opts.Diagnostics == nil || control == "" || control[0] == '\'' {
+		return
+	}
+	if known, _ := knownControl(control); !known {
+		c.diagnose(DiagnosticUnknownControl, c.start, control, fmt.Sprintf("\\%s is not an RTF control word", control))
+		return
+	}
+	switch control {
+	case "ansicpgN":
+		if num != 1252 && num != 28591 {
+			c.diagnose(DiagnosticCodePage, c.start, control, fmt.Sprintf("code page %d is decoded as ISO-8859-1", num))
+		}
+	case "cpgN": // fonts are decoded with the code page of the document
+		if decoding := codePageDecoding(c.codePage); codePageDecoding(num) != decoding {
+			c.diagnose(DiagnosticCodePage, c.start, control, fmt.Sprintf("code page %d is decoded as %s", num, decoding))
+		}
+	case "mac", "pc", "pca":
+		c.diagnose(DiagnosticCodePage, c.start, control, fmt.Sprintf("\\%s character set is decoded as ISO-8859-1", control))
+	}
+}
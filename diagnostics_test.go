@@ -0,0 +1,73 @@
+package rtf2txt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	doc := `{\rtf1\ansi\ansicpg932{\*\generator x;}{\*\bogus y}\pard\f0\foo\f0 hi{\shppict{\pict\pngblip 00}}{\nonshppict{\pict\wmetafile8 00}}}}{\f0 open}{`
+	var diagnostics []Diagnostic
+	r, err := TextWithOptions(strings.NewReader(doc), Options{Diagnostics: func(d Diagnostic) {
+		diagnostics = append(diagnostics, d)
+	}})
+	if err != nil || r.String() != "hiopen" {
+		t.Errorf("expected text %q %v", r.String(), err)
+	}
+	expected := []Diagnostic{
+		{DiagnosticCodePage, 11, "ansicpgN", "code page 932 is decoded as ISO-8859-1", ""},
+		{DiagnosticUnsupportedDestination, 25, "generator", `\*\generator is skipped`, ""},
+		{DiagnosticUnsupportedDestination, 42, "bogus", `\*\bogus is skipped`, ""},
+		{DiagnosticUnknownControl, 59, "foo", `\foo is not an RTF control word`, ""},
+		{DiagnosticIgnoredPicture, 98, "nonshppict", `\nonshppict is skipped in favor of \shppict`, ""},
+		{DiagnosticUnbalancedBraces, 132, "", "closing brace without an open group", ""},
+		{DiagnosticUnbalancedBraces, int64(len(doc)), "", "1 groups are not closed", ""},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatal("expected diagnostics", diagnostics)
+	}
+	for i := range expected {
+		if diagnostics[i] != expected[i] {
+			t.Error("expected diagnostic", expected[i], diagnostics[i])
+		}
+	}
+}
+
+func TestDiagnosticsCodePage(t *testing.T) {
+	tests := []struct {
+		doc      string
+		expected []string
+	}{
+		{`{\rtf1\ansi\ansicpg1252{\fonttbl{\f0\cpg1252 Arial;}{\f1\cpg28591 Times;}}}`, []string{"code page 28591 is decoded as Windows-1252"}},
+		{`{\rtf1\ansi\ansicpg28591{\fonttbl{\f0\cpg1252 Arial;}}}`, []string{"code page 1252 is decoded as ISO-8859-1"}},
+		{`{\rtf1\ansi\ansicpg1251 x}`, []string{"code page 1251 is decoded as ISO-8859-1"}},
+	}
+	for _, test := range tests {
+		var messages []string
+		Convert(strings.NewReader(test.doc), Options{Diagnostics: func(d Diagnostic) { messages = append(messages, d.Message) }})
+		if strings.Join(messages, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("%s: expected %q, got %q", test.doc, test.expected, messages)
+		}
+	}
+}
+
+func TestKnownControl(t *testing.T) {
+	tests := []struct {
+		control     string
+		known, dest bool
+	}{
+		{"par", true, false},
+		{"fonttbl", true, true},
+		{"fN", true, false},
+		{"bN", true, false}, // toggles take a parameter
+		{"rtfN", true, true},
+		{"strikedN", true, false},
+		{"foo", false, false},
+		{"fooN", false, false},
+	}
+	for _, test := range tests {
+		if known, dest := knownControl(test.control); known != test.known || dest != test.dest {
+			t.Error("expected known control", test.control, known, dest)
+		}
+	}
+}
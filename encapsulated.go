@@ -0,0 +1,250 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/EndFirstCorp/peekingReader"
+)
+
+// EncapsulatedFormat is the format of the original content of an RTF
+// document which encapsulates HTML or plain text
+type EncapsulatedFormat string
+
+// Formats of encapsulated content
+const (
+	EncapsulatedHTML EncapsulatedFormat = "html" // \fromhtml1
+	EncapsulatedText EncapsulatedFormat = "text" // \fromtext
+)
+
+// ErrNotEncapsulated is returned by Deencapsulate for RTF documents which
+// don't have \fromhtml1 or \fromtext
+var ErrNotEncapsulated = errors.New("RTF doesn't encapsulate HTML or text")
+
+// encapsulatedSymbols are the characters written for symbol control words
+// in encapsulated content
+var encapsulatedSymbols = map[string]string{
+	"par": "\r\n", "line": "\r\n", "tab": "\t",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	"bullet": "•", "endash": "–", "emdash": "—",
+	"~": " ", "-": "­", "_": "‑",
+}
+
+// encapsulatedGroup is the state of a group of encapsulating RTF
+type encapsulatedGroup struct {
+	htmlrtf bool // RTF only content between \htmlrtf and \htmlrtf0
+	skip    bool // destination which isn't part of the content
+	tag     bool // \*\htmltag or \*\mhtmltag
+	uc      int  // number of fallback characters after \uN
+}
+
+// Deencapsulate is used to get the original HTML or plain text from an
+// io.Reader containing RTF created from it, such as the body of an Outlook
+// message, using the rules of [MS-OXRTFEX]. The content of \*\htmltag
+// destinations is written as is, while text between \htmlrtf and \htmlrtf0
+// is dropped. Escaped bytes are decoded with the code page of \ansicpgN.
+// ErrNotEncapsulated is returned for any other RTF. Of the options only
+// Limits and Lenient apply. Errors reading the RTF are a *ParseError
+func Deencapsulate(r io.Reader, opts Options) (*bytes.Buffer, EncapsulatedFormat, error) {
+	d := &decapsulator{r: newPositionReader(peekingReader.NewBufReader(limitInput(r, opts.Limits))), opts: opts,
+		groups: []encapsulatedGroup{{uc: 1}}, skipTag: -1, codePage: defaultCodePage}
+	for {
+		b, err := d.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err == nil {
+			err = d.readByte(b)
+		}
+		if err == nil {
+			err = d.err
+		}
+		if err != nil {
+			err = newParseError(d.r, d.control, len(d.groups)-1, err)
+			truncated := errors.Is(err, ErrTruncated)
+			if !opts.Lenient || !truncated && !errors.Is(err, ErrMalformedControl) {
+				return nil, d.format, err
+			}
+			if truncated {
+				break
+			}
+		}
+	}
+	if d.format == "" {
+		return nil, d.format, ErrNotEncapsulated
+	}
+	return &d.out, d.format, nil
+}
+
+// decapsulator holds the state needed while deencapsulating a single RTF
+// document
+type decapsulator struct {
+	r        *positionReader
+	opts     Options
+	out      bytes.Buffer
+	format   EncapsulatedFormat
+	groups   []encapsulatedGroup
+	skipTag  int    // \*\htmltagN which follows \*\mhtmltagN with the same N
+	fallback int    // fallback characters of \uN still to be skipped
+	codePage int    // code page of \'hh escapes
+	control  string // last control word read
+	controls int    // number of control words read
+	err      error  // error found while writing, returned by Deencapsulate
+}
+
+func (d *decapsulator) group() *encapsulatedGroup {
+	return &d.groups[len(d.groups)-1]
+}
+
+// write writes s to the content unless the group isn't part of it
+func (d *decapsulator) write(s string) {
+	if g := d.group(); !g.tag && (g.skip || g.htmlrtf) {
+		return
+	}
+	if err := d.opts.Limits.checkOutput(d.out.Len() + len(s)); err != nil {
+		if d.err == nil {
+			d.err = err
+		}
+		return
+	}
+	d.out.WriteString(s)
+}
+
+func (d *decapsulator) readByte(b byte) error {
+	switch b {
+	case '{':
+		d.groups = append(d.groups, *d.group())
+		d.group().tag = false
+		d.fallback = 0
+		return d.opts.Limits.checkDepth(len(d.groups) - 1)
+	case '}':
+		if len(d.groups) > 1 {
+			d.groups = d.groups[:len(d.groups)-1]
+		}
+		d.fallback = 0
+	case '\r', '\n':
+	case '\\':
+		return d.readControl()
+	default:
+		if d.fallback > 0 {
+			d.fallback--
+			return nil
+		}
+		d.write(string(rune(b)))
+	}
+	return nil
+}
+
+// tokenizeControl reads a control word, counting it against the limit
+func (d *decapsulator) tokenizeControl() (string, int, error) {
+	control, num, err := tokenizeControl(d.r)
+	if err != nil {
+		return "", -1, err
+	}
+	d.control = control
+	d.controls++
+	return control, num, d.opts.Limits.checkControls(d.controls)
+}
+
+func (d *decapsulator) readControl() error {
+	control, num, err := d.tokenizeControl()
+	if err != nil {
+		return err
+	}
+	g := d.group()
+	if control == "*" {
+		if p, err := d.r.Peek(1); err != nil || p[0] != '\\' {
+			g.skip = true
+			return nil
+		}
+		d.r.ReadByte()
+		if control, num, err = d.tokenizeControl(); err != nil {
+			return err
+		}
+		switch {
+		case control == "htmltagN" && num == d.skipTag:
+			g.skip, d.skipTag = true, -1
+		case control == "htmltagN" || control == "htmltag":
+			g.tag = true
+		case control == "mhtmltagN" || control == "mhtmltag":
+			g.tag, d.skipTag = true, num
+		default:
+			g.skip = true
+		}
+		skipDelimiter(d.r)
+		return nil
+	}
+	if isUnicode, u := getUnicode(control); isUnicode {
+		if d.fallback > 0 {
+			d.fallback--
+			return nil
+		}
+		d.write(decodeCodePage(u, d.codePage))
+		return nil
+	}
+	if control == "" { // escaped character or a backslash before a new line
+		p, err := d.r.Peek(1)
+		if err != nil {
+			return err
+		}
+		d.r.ReadByte()
+		switch p[0] {
+		case '\\', '{', '}':
+			d.write(string(p[0]))
+		case '\r', '\n':
+			d.write("\r\n")
+		default:
+			d.write(encapsulatedSymbols[string(p[0])])
+		}
+		return nil
+	}
+	skipDelimiter(d.r)
+
+	switch control {
+	case "fromhtmlN":
+		if num == 1 {
+			d.format = EncapsulatedHTML
+		}
+	case "fromtext":
+		d.format = EncapsulatedText
+	case "ansi", "mac", "pc", "pca":
+		d.codePage = characterSets[control]
+	case "ansicpgN":
+		d.codePage = num
+	case "htmlrtf":
+		g.htmlrtf = true
+	case "htmlrtfN":
+		g.htmlrtf = num != 0
+	case "ucN":
+		g.uc = num
+	case "uN":
+		if num < 0 {
+			num += 65536
+		}
+		d.write(string(rune(num)))
+		d.fallback = g.uc
+	case "binN":
+		if err := d.opts.Limits.checkBinary(num); err != nil {
+			return err
+		}
+		if _, err := handleBinary(d.r, control, num); err != nil {
+			return err
+		}
+	case "rtfN", "field", "fldrslt":
+	default:
+		if symbol, ok := encapsulatedSymbols[control]; ok {
+			d.write(symbol)
+		} else if _, dest := knownControl(control); dest {
+			g.skip = true
+		}
+	}
+	return nil
+}
+
+// skipDelimiter consumes the space which can end a control word
+func skipDelimiter(r peekingReader.Reader) {
+	if p, err := r.Peek(1); err == nil && p[0] == ' ' {
+		r.ReadByte()
+	}
+}
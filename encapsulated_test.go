@@ -0,0 +1,97 @@
+package rtf2txt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDeencapsulate(t *testing.T) {
+	html := `{\rtf1\ansi\ansicpg1252\fromhtml1 \deff0{\fonttbl
+{\f0\fswiss Arial;}
+{\f1\fmodern Courier New;}}
+{\colortbl\red0\green0\blue0;\red0\green0\blue255;}
+\uc1\pard\plain\deftab360 \f0\fs24
+{\*\htmltag19 <html>}
+{\*\htmltag34 <head>}
+{\*\htmltag161 <style>}
+{\*\htmltag241 <!--\par body\par \{\par font-family: Arial\par \}\par -->}
+{\*\htmltag169 </style>}
+{\*\htmltag42 </head>}
+{\*\htmltag50 <body>}\htmlrtf {\htmlrtf0
+{\*\htmltag64 <p>}
+{\*\htmltag84 <b>}\htmlrtf {\b\htmlrtf0 hello caf\'e9\~world\htmlrtf }\htmlrtf0
+{\*\htmltag92 </b>}
+{\*\mhtmltag84 <img src="cid:image001">}{\*\htmltag84 <img src="image001.jpg">}
+{\*\htmltag72 </p>}
+\htmlrtf \par }\htmlrtf0
+{\*\htmltag58 </body>}
+{\*\htmltag27 </html>}}`
+	r, format, err := Deencapsulate(strings.NewReader(html), Options{})
+	expected := "<html><head><style><!--\r\nbody\r\n{\r\nfont-family: Arial\r\n}\r\n--></style></head><body>" +
+		"<p><b>hello café world</b><img src=\"cid:image001\"></p></body></html>"
+	if err != nil || format != EncapsulatedHTML || r.String() != expected {
+		t.Errorf("expected html %q %s %v", r, format, err)
+	}
+
+	text := `{\rtf1\ansi\fromtext \deff0{\fonttbl {\f0 Courier;}}{\*\generator x;}\f0 Line one\par Tab\tab end\par \u8364? euro\
+{\*\htmltag8 ignored}}`
+	r, format, err = Deencapsulate(strings.NewReader(text), Options{})
+	if err != nil || format != EncapsulatedText || r.String() != "Line one\r\nTab\tend\r\n€ euro\r\nignored" {
+		t.Errorf("expected text %q %s %v", r, format, err)
+	}
+
+	if _, _, err := Deencapsulate(strings.NewReader(`{\rtf1\ansi plain}`), Options{}); err != ErrNotEncapsulated {
+		t.Error("expected not encapsulated", err)
+	}
+	if _, _, err := Deencapsulate(strings.NewReader(`{\rtf1\fromtext\12}`), Options{}); !errors.Is(err, ErrMalformedControl) {
+		t.Error("expected malformed control", err)
+	}
+}
+
+func TestDeencapsulateErrors(t *testing.T) {
+	const truncated = "{\\rtf1\\fromtext\npartial \\'9"
+	_, _, err := Deencapsulate(strings.NewReader(truncated), Options{})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrTruncated) || parseErr.Offset != int64(len(truncated)) ||
+		parseErr.Line != 2 || parseErr.Control != "fromtext" || parseErr.Depth != 1 {
+		t.Errorf("expected truncated parse error %+v", err)
+	}
+	r, _, err := Deencapsulate(strings.NewReader(truncated), Options{Lenient: true})
+	if err != nil || r.String() != "partial " {
+		t.Errorf("expected lenient content %q %v", r, err)
+	}
+
+	tests := []struct {
+		doc    string
+		limits Limits
+		limit  string
+	}{
+		{`{\rtf1\fromtext hello}`, Limits{MaxInputBytes: 10}, "MaxInputBytes"},
+		{`{\rtf1\fromtext hello}`, Limits{MaxOutputBytes: 4}, "MaxOutputBytes"},
+		{`{\rtf1\fromtext{{x}}}`, Limits{MaxDepth: 2}, "MaxDepth"},
+		{`{\rtf1\fromtext\b\i x}`, Limits{MaxControlWords: 3}, "MaxControlWords"},
+		{`{\rtf1\fromtext{\*\foo\bin5 12345}}`, Limits{MaxBinaryBytes: 4}, "MaxBinaryBytes"},
+	}
+	for _, test := range tests {
+		_, _, err := Deencapsulate(strings.NewReader(test.doc), Options{Limits: test.limits, Lenient: true})
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != test.limit || !errors.As(err, &parseErr) {
+			t.Errorf("%s: expected limit error %v", test.limit, err)
+		}
+	}
+}
+
+func TestDeencapsulateCodePage(t *testing.T) {
+	text := `{\rtf1\ansi\ansicpg1252\fromtext \'93quoted\'94 \'96 caf\'e9 \'81}`
+	r, _, err := Deencapsulate(strings.NewReader(text), Options{})
+	if err != nil || r.String() != "“quoted” – café \u0081" {
+		t.Errorf("expected Windows-1252 characters %q %v", r, err)
+	}
+
+	text = `{\rtf1\ansi\ansicpg28591\fromtext \'93}`
+	r, _, err = Deencapsulate(strings.NewReader(text), Options{})
+	if err != nil || r.String() != "\u0093" {
+		t.Errorf("expected ISO-8859-1 characters %q %v", r, err)
+	}
+}
@@ -0,0 +1,107 @@
+package rtf2txt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/EndFirstCorp/peekingReader"
+)
+
+// Kinds of parse errors. Use errors.Is to check for them. Limit violations
+// are reported as a *LimitError, which can be found with errors.As
+var (
+	// ErrTruncated is matched by errors for documents which end in the
+	// middle of a control word, its parameters or binary data
+	ErrTruncated = errors.New("Unexpected end of RTF data")
+
+	// ErrMalformedControl is matched by errors for control words which
+	// can't be parsed
+	ErrMalformedControl = errors.New("Unexpected control sequence")
+)
+
+// ParseError is returned when a document can't be converted. It records
+// where in the RTF data the problem was found
+type ParseError struct {
+	Offset  int64  // offset of the byte after the problem, or -1 if unknown
+	Line    int    // line of Offset, starting at 1
+	Column  int    // column of Offset in bytes, starting at 1
+	Control string // last control word read, such as "fonttbl" or "fN"
+	Depth   int    // nesting of groups
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%s at line %d, column %d (offset %d, depth %d)", e.message(), e.Line, e.Column, e.Offset, e.Depth)
+	if e.Control != "" {
+		msg += fmt.Sprintf(" after \\%s", e.Control)
+	}
+	return msg
+}
+
+// message returns the message of Err, reporting input which ended too
+// early as ErrTruncated
+func (e *ParseError) message() string {
+	if errors.Is(e, ErrTruncated) && !errors.Is(e.Err, ErrTruncated) {
+		return ErrTruncated.Error()
+	}
+	return e.Err.Error()
+}
+
+// MarshalJSON writes Err as its message, as errors have no exported fields
+func (e *ParseError) MarshalJSON() ([]byte, error) {
+	type fields ParseError
+	return json.Marshal(struct {
+		*fields
+		Err string
+	}{(*fields)(e), e.message()})
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports input which ended too early as ErrTruncated
+func (e *ParseError) Is(target error) bool {
+	return target == ErrTruncated && (errors.Is(e.Err, io.EOF) || errors.Is(e.Err, io.ErrUnexpectedEOF))
+}
+
+// parseError records the position of the converter with err
+func (c *converter) parseError(err error) error {
+	if err == nil || err == c.ctx.Err() {
+		return err
+	}
+	if _, ok := err.(*ParseError); ok {
+		return err
+	}
+	return newParseError(c.r, c.control, len(c.groups)-1, err)
+}
+
+// newParseError records the position of r with err. The position is only
+// known when r is a positionReader
+func newParseError(r peekingReader.Reader, control string, depth int, err error) *ParseError {
+	e := &ParseError{Offset: -1, Control: control, Depth: depth, Err: err}
+	if p, ok := r.(*positionReader); ok {
+		e.Offset, e.Line, e.Column = p.offset, p.line, p.column
+	}
+	return e
+}
+
+// tolerate returns whether the conversion must stop because of err and the
+// error to return. In lenient mode truncated input ends the conversion
+// without an error and malformed control words are skipped. Both are kept
+// as warnings
+func (c *converter) tolerate(err error) (bool, error) {
+	err = c.parseError(err)
+	truncated := errors.Is(err, ErrTruncated)
+	if !c.opts.Lenient || !truncated && !errors.Is(err, ErrMalformedControl) {
+		return true, err
+	}
+	c.warnings = append(c.warnings, err.(*ParseError))
+	if truncated {
+		c.finish()
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,94 @@
+package rtf2txt
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/EndFirstCorp/peekingReader"
+)
+
+func TestParseError(t *testing.T) {
+	_, err := Text(strings.NewReader("{\\rtf1\\pard\n{\\f0 hello\\f463 hi"))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrTruncated) || errors.Is(err, ErrMalformedControl) {
+		t.Fatal("expected truncated parse error", err)
+	}
+	if parseErr.Offset != 30 || parseErr.Line != 2 || parseErr.Column != 19 || parseErr.Control != "fN" || parseErr.Depth != 2 {
+		t.Error("expected position", *parseErr)
+	}
+	if err.Error() != `Unexpected end of RTF data at line 2, column 19 (offset 30, depth 2) after \fN` {
+		t.Error("expected error message", err.Error())
+	}
+
+	_, err = Text(strings.NewReader(`{\rtf1\12ab hi}`))
+	if !errors.As(err, &parseErr) || !errors.Is(err, ErrMalformedControl) || errors.Is(err, ErrTruncated) {
+		t.Error("expected malformed control", err)
+	}
+	if parseErr.Offset != 8 || parseErr.Line != 1 || parseErr.Column != 9 || parseErr.Control != "rtfN" {
+		t.Error("expected position", *parseErr)
+	}
+
+	_, err = Text(strings.NewReader(`{\rtf1{\pict\bin-5 abc}}`))
+	if !errors.Is(err, ErrMalformedControl) {
+		t.Error("expected malformed \\bin", err)
+	}
+
+	_, err = TextWithOptions(strings.NewReader(`{\rtf1{{\f0 deep}}}`), Options{Limits: Limits{MaxDepth: 2}})
+	var limitErr *LimitError
+	if !errors.As(err, &parseErr) || !errors.As(err, &limitErr) || errors.Is(err, ErrTruncated) || parseErr.Depth != 3 {
+		t.Error("expected limit error", err)
+	}
+}
+
+func TestParseErrorJSON(t *testing.T) {
+	err := &ParseError{Offset: 30, Line: 2, Column: 19, Control: "fN", Depth: 2, Err: io.EOF}
+	b, jsonErr := json.Marshal(err)
+	expected := `{"Offset":30,"Line":2,"Column":19,"Control":"fN","Depth":2,"Err":"Unexpected end of RTF data"}`
+	if jsonErr != nil || string(b) != expected {
+		t.Error("expected error message in json", jsonErr, string(b))
+	}
+}
+
+func TestPositionReader(t *testing.T) {
+	c := newConverter(peekingReader.NewMemReader([]byte("ab\ncd\r\nef")), Options{})
+	c.r.ReadBytes(4)
+	if line, column := c.position(); line != 2 || column != 2 || c.offset() != 4 {
+		t.Error("expected line 2, column 2", line, column, c.offset())
+	}
+	c.r.ReadByte()
+	c.r.ReadByte()
+	c.r.ReadRune()
+	if line, column := c.position(); line != 3 || column != 1 || c.offset() != 7 {
+		t.Error("expected line 3, column 1", line, column, c.offset())
+	}
+}
+
+func TestLenient(t *testing.T) {
+	const truncated = `{\rtf1\pard\f0 hello\par\f463 hi`
+	if _, err := Text(strings.NewReader(truncated)); !errors.Is(err, ErrTruncated) {
+		t.Error("expected strict mode to fail", err)
+	}
+	r, err := TextWithOptions(strings.NewReader(truncated), Options{Lenient: true})
+	if err != nil || r.String() != "hello hi" {
+		t.Errorf("expected best-effort text %q %v", r.String(), err)
+	}
+	d, err := Convert(strings.NewReader(truncated), Options{Lenient: true})
+	if err != nil || len(d.Warnings) != 1 || !errors.Is(d.Warnings[0], ErrTruncated) || d.Warnings[0].Offset != int64(len(truncated)) {
+		t.Error("expected truncated warning", err, d.Warnings)
+	}
+
+	d, err = Convert(strings.NewReader(`{\rtf1\pard\f0 one \12 two\bin-1 three}`), Options{Lenient: true})
+	if err != nil || d.Text != "one 2 two three" || len(d.Warnings) != 2 ||
+		!errors.Is(d.Warnings[0], ErrMalformedControl) || !errors.Is(d.Warnings[1], ErrMalformedControl) {
+		t.Errorf("expected malformed controls to be skipped %q %v %v", d.Text, err, d.Warnings)
+	}
+
+	_, err = Convert(strings.NewReader(`{\rtf1{{\f0 deep}}}`), Options{Lenient: true, Limits: Limits{MaxDepth: 2}})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Error("expected limits to be enforced", err)
+	}
+}
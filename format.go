@@ -0,0 +1,261 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// FormatOptions change how Format writes RTF
+type FormatOptions struct {
+	RemoveRsids bool   // \rsidN, \insrsidN, \charrsidN, the other revision save identifiers and \*\rsidtbl are removed
+	Indent      string // written for each group a line is nested in, only where the text is ignored. The text of the document is never indented
+}
+
+// capturedDestinations are the destinations whose text the converter keeps,
+// so that a list table which ignores text doesn't ignore theirs
+var capturedDestinations = destinationsUsed(useCaptured)
+
+// Format is used to copy RTF from an io.Reader to an io.Writer in a form
+// which is easier to read and diff. Each group starts on a new line, new
+// lines which are ignored are removed and control words only keep their
+// delimiting space before text, so that the text of the output is the same.
+// Revision save identifiers are only removed where that doesn't change the
+// text either. Spaces and tabs are text to RTF readers, so lines are only
+// indented inside groups whose text is ignored, such as unknown \*
+// destinations and the list tables. Groups of the text of the document,
+// including fields, headers and footnotes, are not indented however deep
+// they are. Other whitespace inside ignored groups is removed
+func Format(w io.Writer, r io.Reader, opts FormatOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	tokens, err := lex(data)
+	if err != nil {
+		return err
+	}
+	f := &formatter{data: data, tokens: tokens, opts: opts, params: paramControls(tokens), quiet: quietTokens(tokens),
+		removed: make([]bool, len(tokens)), spaced: make(map[int]bool), texts: make(map[int]string)}
+	f.keep()
+	_, err = w.Write(f.format())
+	return err
+}
+
+// formatter decides which tokens Format keeps and how it writes them
+type formatter struct {
+	data    []byte
+	tokens  []token
+	opts    FormatOptions
+	params  []string       // control word reading the text after each token as its parameter, or ""
+	quiet   []bool         // tokens in a group whose text is ignored
+	removed []bool         // tokens which aren't written
+	spaced  map[int]bool   // control words which need a delimiting space for the text of removed tokens
+	texts   map[int]string // text tokens as they are written
+}
+
+// keep decides which tokens are removed and how text tokens are written
+func (f *formatter) keep() {
+	tokens := f.tokens
+	ends := groupEnds(tokens)
+	prev := -1 // last token which is kept
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case f.opts.RemoveRsids && t.kind == tokenGroupStart:
+			if dest, _ := groupDest(tokens, i); dest == "rsidtbl" && ends[i] < len(tokens) && !f.beforeText(ends[i]) {
+				for j := i; j <= ends[i]; j++ {
+					f.removed[j] = true
+				}
+				i = ends[i]
+				continue
+			}
+		case f.opts.RemoveRsids && t.isWord() && isRsid(t.control):
+			if !f.beforeText(i) {
+				f.removed[i] = true
+				continue
+			}
+			// the text after the identifier is read the same way by the control word before it
+			if prev >= 0 && tokens[prev].isWord() && f.params[prev] != "" && f.params[prev] != "uN" && !showsParams(f.params[prev]) {
+				f.removed[i] = true
+				if !tokens[prev].delimited(f.data) && t.delimited(f.data) {
+					f.spaced[prev] = true
+				}
+				continue
+			}
+		case t.kind == tokenText && f.quiet[i] && len(bytes.Trim(f.data[t.start:t.end], " \t\r\n")) == 0:
+			f.removed[i] = true
+			continue
+		case t.kind == tokenText:
+			control := ""
+			if prev >= 0 {
+				control = f.params[prev]
+			}
+			text := formatText(f.data[t.start:t.end], control, i == len(tokens)-1)
+			if text == "" {
+				f.removed[i] = true
+				continue
+			}
+			f.texts[i] = text
+		}
+		prev = i
+	}
+}
+
+// beforeText returns whether the token after i is text other than new lines
+func (f *formatter) beforeText(i int) bool {
+	if i+1 >= len(f.tokens) || f.tokens[i+1].kind != tokenText {
+		return false
+	}
+	next := f.tokens[i+1]
+	return len(bytes.Trim(f.data[next.start:next.end], "\r\n")) > 0
+}
+
+// next returns the index of the first token after i which is kept, or
+// len(tokens)
+func (f *formatter) next(i int) int {
+	for i++; i < len(f.tokens) && f.removed[i]; i++ {
+	}
+	return i
+}
+
+// format writes the tokens which are kept
+func (f *formatter) format() []byte {
+	var b bytes.Buffer
+	depth := 0
+	newLine := false // a new line starts before the next token
+	last := -1
+	for i, t := range f.tokens {
+		if f.removed[i] {
+			continue
+		}
+		last = i
+		if t.kind == tokenGroupStart && b.Len() > 0 {
+			newLine = true
+		}
+		if newLine {
+			b.WriteByte('\n')
+			if f.quiet[i] {
+				b.WriteString(strings.Repeat(f.opts.Indent, depth))
+			}
+			newLine = false
+		}
+		next := f.next(i)
+		nextText := next < len(f.tokens) && f.tokens[next].kind == tokenText
+		switch t.kind {
+		case tokenGroupStart:
+			b.WriteByte('{')
+			depth++
+		case tokenGroupEnd:
+			b.WriteByte('}')
+			if depth > 0 {
+				depth--
+			}
+			// a new line would end the text read as a parameter after a skipped group
+			newLine = next < len(f.tokens) && f.tokens[next].kind != tokenGroupEnd && (!nextText || f.params[i] == "")
+		case tokenText:
+			b.WriteString(f.texts[i])
+		case tokenControl:
+			raw := f.data[t.start:t.end]
+			if t.isWord() && t.control != "binN" && !nextText {
+				raw = bytes.TrimSuffix(raw, []byte(" "))
+			}
+			b.Write(raw)
+			if f.spaced[i] && nextText && !bytes.HasSuffix(raw, []byte(" ")) {
+				b.WriteByte(' ')
+			}
+		default:
+			b.Write(f.data[t.start:t.end])
+		}
+	}
+	if last >= 0 && f.tokens[last].kind == tokenGroupEnd && f.params[last] == "" {
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// paramControls returns the control word which reads the text after each
+// token as its parameter, or "" for tokens followed by the text of the
+// document. The end of a group skipped after \* is followed by the
+// parameter of \*
+func paramControls(tokens []token) []string {
+	params := make([]string, len(tokens))
+	ends := groupEnds(tokens)
+	var open []int
+	for i, t := range tokens {
+		switch {
+		case t.kind == tokenGroupStart:
+			open = append(open, i)
+		case t.kind == tokenGroupEnd:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case t.control == "*":
+			known := i+1 < len(tokens) && tokens[i+1].isWord() && extendedDestinations[tokens[i+1].control]
+			if len(open) > 0 && !known {
+				if end := ends[open[len(open)-1]]; end < len(tokens) {
+					params[end] = "*"
+				}
+			}
+		case t.isWord() && t.control != "binN" && t.control != "nonshppict":
+			params[i] = t.control
+		}
+	}
+	return params
+}
+
+// quietTokens returns whether the text at each token is ignored, because
+// its group is skipped after \* or \nonshppict or is captured for the list
+// tables
+func quietTokens(tokens []token) []bool {
+	type state struct{ skipped, ignored bool }
+	quiet := make([]bool, len(tokens))
+	groups := []state{{}}
+	for i, t := range tokens {
+		g := &groups[len(groups)-1]
+		quiet[i] = g.skipped || g.ignored
+		switch {
+		case t.kind == tokenGroupStart:
+			groups = append(groups, *g)
+		case t.kind == tokenGroupEnd:
+			if len(groups) > 1 {
+				groups = groups[:len(groups)-1]
+			}
+		case t.control == "*":
+			if i+1 >= len(tokens) || !tokens[i+1].isWord() || !extendedDestinations[tokens[i+1].control] {
+				g.skipped = true
+			}
+		case t.control == "nonshppict":
+			g.skipped = true
+		case t.control == "listtable" || t.control == "listoverridetable":
+			g.ignored = true
+		case capturedDestinations[t.control]:
+			g.ignored = false
+		}
+	}
+	return quiet
+}
+
+// formatText returns text as Format writes it after a token whose parameter
+// it is read as, or "" for the text of the document. New lines are removed,
+// except the one which ends a parameter before more text or the end of the
+// document
+func formatText(text []byte, control string, last bool) string {
+	removeNewLines := strings.NewReplacer("\r", "", "\n", "")
+	if control == "" {
+		return removeNewLines.Replace(string(text))
+	}
+	end := bytes.IndexAny(text, "\r\n;")
+	if end < 0 {
+		return string(text)
+	}
+	rest := removeNewLines.Replace(string(text[end+1:]))
+	switch {
+	case text[end] == ';':
+		return string(text[:end+1]) + rest
+	case rest == "" && !last:
+		return string(text[:end])
+	default:
+		return string(text[:end]) + "\n" + rest
+	}
+}
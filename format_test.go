@@ -0,0 +1,111 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	in := "{\\rtf1\\ansi {\\fonttbl{\\f0 Arial;}}\r\n{\\*\\rsidtbl \\rsid1\\rsid2}\r\n" +
+		"\\pard\\plain\\f0\\b \\insrsid1 \\par\r\n\\tab\r\nb{\\*\\foo x}hidden\\par{\\b c}\r\n d\\line\r\n}"
+	tests := []struct {
+		opts     FormatOptions
+		expected string
+	}{
+		{FormatOptions{}, "{\\rtf1\\ansi\n{\\fonttbl\n{\\f0 Arial;}}\n{\\*\\rsidtbl\\rsid1\\rsid2}\n" +
+			"\\pard\\plain\\f0\\b\\insrsid1\\par\\tab\nb\n{\\*\\foo x}hidden\\par\n{\\b c}\n d\\line}\n"},
+		{FormatOptions{RemoveRsids: true}, "{\\rtf1\\ansi\n{\\fonttbl\n{\\f0 Arial;}}\n" +
+			"\\pard\\plain\\f0\\b\\par\\tab\nb\n{\\*\\foo x}hidden\\par\n{\\b c}\n d\\line}\n"},
+		{FormatOptions{Indent: "  "}, "{\\rtf1\\ansi\n{\\fonttbl\n{\\f0 Arial;}}\n{\\*\\rsidtbl\\rsid1\\rsid2}\n" +
+			"\\pard\\plain\\f0\\b\\insrsid1\\par\\tab\nb\n{\\*\\foo x}hidden\\par\n{\\b c}\n d\\line}\n"},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := Format(&b, strings.NewReader(in), test.opts); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != test.expected {
+			t.Errorf("%+v: expected %q, got %q", test.opts, test.expected, b.String())
+		}
+	}
+}
+
+func TestFormatIndent(t *testing.T) {
+	in := "{\\rtf1{\\*\\listtable{\\list{\\listlevel{\\leveltext{\\b x}}}{\\listname ;}}}" +
+		"{\\*\\foo{\\bar{\\baz}}}{\\b shown}{\\field{\\fldrslt{\\b deep}}}}"
+	expected := "{\\rtf1\n{\\*\\listtable\n    {\\list\n      {\\listlevel\n        {\\leveltext\n{\\b x}}}\n      {\\listname ;}}}\n" +
+		"{\\*\\foo\n    {\\bar\n      {\\baz}}}\n{\\b shown}\n{\\field\n{\\fldrslt\n{\\b deep}}}}\n"
+	var b bytes.Buffer
+	if err := Format(&b, strings.NewReader(in), FormatOptions{Indent: "  "}); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestFormatKeepsText(t *testing.T) {
+	docs := []string{
+		`{\rtf1{\*\rsidtbl \rsid1}kept\par\b\insrsid1 ` + "\n" + `Hello\b0\charrsid2 \par}`,
+		`{\rtf1\fcs0\insrsid1;x\par\f0\insrsid2 y\par\plain\insrsid3 z\par}`,
+		`{\rtf1\ltrch\fcs1\insrsid4\charrsid5 hidden\par\u8364\insrsid6 ?\par}`,
+		"{\\rtf1{\\*\\foo x}\r\nshown{\\*\\bar y}\\insrsid7 z\\par}",
+		"{\\rtf1{\\*\\listtable{\\list\\listid1 {\\listlevel{\\leveltext\\'02\\'00.;}}\t}}{\\listtext\\tab 1.}\\ls1 a\\par}",
+	}
+	files, err := filepath.Glob("testdata/*.rtf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs = append(docs, string(data))
+	}
+
+	for _, in := range docs {
+		expected, err := Text(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, opts := range []FormatOptions{{}, {RemoveRsids: true}, {Indent: "  "}, {Indent: "\t", RemoveRsids: true}} {
+			var b bytes.Buffer
+			if err := Format(&b, strings.NewReader(in), opts); err != nil {
+				t.Fatal(err)
+			}
+			actual, err := Text(bytes.NewReader(b.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual.String() != expected.String() {
+				t.Errorf("%+v: expected %q, got %q for %q", opts, expected, actual, b.String())
+			}
+
+			var again bytes.Buffer
+			if err := Format(&again, bytes.NewReader(b.Bytes()), opts); err != nil {
+				t.Fatal(err)
+			}
+			if again.String() != b.String() {
+				t.Errorf("%+v: expected formatting twice to change nothing, got %q from %q", opts, again.String(), b.String())
+			}
+		}
+	}
+}
+
+func TestFormatRemovesRsids(t *testing.T) {
+	data, err := os.ReadFile("testdata/np.new.rtf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := Format(&b, bytes.NewReader(data), FormatOptions{RemoveRsids: true}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "rsid") {
+		t.Errorf("expected no revision save identifiers, got %q", b.String())
+	}
+}
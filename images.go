@@ -0,0 +1,119 @@
+package rtf2txt
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+// ImageFormat is the format of the data of an Image
+type ImageFormat string
+
+// Image formats used in the \pict destination
+const (
+	ImageUnknown ImageFormat = ""
+	ImagePNG     ImageFormat = "png"     // \pngblip
+	ImageJPEG    ImageFormat = "jpeg"    // \jpegblip
+	ImageEMF     ImageFormat = "emf"     // \emfblip
+	ImageWMF     ImageFormat = "wmf"     // \wmetafileN
+	ImageDIB     ImageFormat = "dib"     // \dibitmapN
+	ImageBitmap  ImageFormat = "bmp"     // \wbitmapN
+	ImageMacPICT ImageFormat = "macpict" // \macpict
+	ImageOS2     ImageFormat = "os2meta" // \pmmetafileN
+)
+
+// Image is a picture found in a \pict destination
+type Image struct {
+	Format ImageFormat
+
+	// Width and Height are from \picwN and \pichN. They are in pixels for
+	// bitmaps and in hundredths of a millimeter for metafiles
+	Width, Height int
+
+	// GoalWidth and GoalHeight are the desired size in twips from \picwgoalN
+	// and \pichgoalN
+	GoalWidth, GoalHeight int
+
+	// ScaleX and ScaleY are the scaling percentages from \picscalexN and
+	// \picscaleyN
+	ScaleX, ScaleY int
+
+	// Data is the decoded picture from either the hex data or \binN
+	Data []byte
+}
+
+// Images is used to get the pictures from an io.Reader containing RTF data
+func Images(r io.Reader) ([]Image, error) {
+	d, err := Convert(r, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return d.Images, nil
+}
+
+// handlePicture updates the picture being read for the \pict destination
+// and its properties
+func (c *converter) handlePicture(control string, num int) {
+	if control == "pict" {
+		c.picture = &Image{ScaleX: 100, ScaleY: 100}
+		picture := c.picture
+		c.capture(destPicture, true, func(text string) {
+			if picture.Data == nil {
+				picture.Data = decodeHex(text)
+			}
+			c.images = append(c.images, *picture)
+		})
+		return
+	}
+	if c.group().dest != destPicture {
+		return
+	}
+	p := c.picture
+	switch control {
+	case "pngblip":
+		p.Format = ImagePNG
+	case "jpegblip":
+		p.Format = ImageJPEG
+	case "emfblip":
+		p.Format = ImageEMF
+	case "wmetafileN":
+		p.Format = ImageWMF
+	case "dibitmapN":
+		p.Format = ImageDIB
+	case "wbitmapN":
+		p.Format = ImageBitmap
+	case "macpict":
+		p.Format = ImageMacPICT
+	case "pmmetafileN":
+		p.Format = ImageOS2
+	case "picwN":
+		p.Width = num
+	case "pichN":
+		p.Height = num
+	case "picwgoalN":
+		p.GoalWidth = num
+	case "pichgoalN":
+		p.GoalHeight = num
+	case "picscalexN":
+		p.ScaleX = num
+	case "picscaleyN":
+		p.ScaleY = num
+	}
+}
+
+// decodeHex decodes hex data, ignoring any whitespace or other characters
+// that are not hex digits. A trailing half byte is dropped
+func decodeHex(text string) []byte {
+	digits := make([]byte, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		if isHexDigit(text[i]) {
+			digits = append(digits, text[i])
+		}
+	}
+	data := make([]byte, len(digits)/2)
+	hex.Decode(data, digits[:len(data)*2])
+	return data
+}
+
+func isHexDigit(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F'
+}
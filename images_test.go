@@ -0,0 +1,34 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestImages(t *testing.T) {
+	const doc = `{\rtf1\pard\f0 Logo {\*\shppict{\pict\pngblip\picw16\pich8\picwgoal240\pichgoal120\picscalex50\picscaley75
+89504e47
+0d0a1a0a}}{\nonshppict{\pict\wmetafile8\picw16\pich8 0100}}\f0  after{\pict\jpegblip\bin4 ` + "\xff\xd8}\xff" + `}\f0  end\par}`
+	d, err := Convert(strings.NewReader(doc), Options{})
+	if err != nil || len(d.Images) != 2 {
+		t.Fatal("expected images", err, d)
+	}
+	if d.Text != "Logo  after end " {
+		t.Error("expected picture data to be excluded from text", d.Text)
+	}
+	png := d.Images[0]
+	if png.Format != ImagePNG || png.Width != 16 || png.Height != 8 || png.GoalWidth != 240 || png.GoalHeight != 120 ||
+		png.ScaleX != 50 || png.ScaleY != 75 || !bytes.Equal(png.Data, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Error("expected png image", png)
+	}
+	jpeg := d.Images[1]
+	if jpeg.Format != ImageJPEG || jpeg.ScaleX != 100 || !bytes.Equal(jpeg.Data, []byte("\xff\xd8}\xff")) {
+		t.Error("expected binary jpeg image", jpeg)
+	}
+
+	images, err := Images(strings.NewReader(`{\pict\dibitmap0 0a0b0}`))
+	if err != nil || len(images) != 1 || images[0].Format != ImageDIB || !bytes.Equal(images[0].Data, []byte{0x0a, 0x0b}) {
+		t.Error("expected dib image", err, images)
+	}
+}
@@ -0,0 +1,101 @@
+package rtf2txt
+
+import (
+	"fmt"
+	"io"
+)
+
+// Limits bound the resources used to convert a document so that untrusted
+// RTF can be processed safely. A limit of zero means there is no limit
+type Limits struct {
+	MaxInputBytes   int64 // bytes of RTF read
+	MaxBinaryBytes  int   // size of the data of a single \binN
+	MaxDepth        int   // nesting of groups
+	MaxOutputBytes  int   // bytes of document text written, including the text and data captured from destinations
+	MaxControlWords int   // number of control words read
+}
+
+// LimitError is returned when a document exceeds one of its Limits
+type LimitError struct {
+	Limit string // name of the field of Limits, such as "MaxDepth"
+	Value int64  // value of the limit which was exceeded
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("Document exceeds limit. %s is %d", e.Limit, e.Value)
+}
+
+// limitedReader returns a LimitError once more than max bytes are read
+type limitedReader struct {
+	r         io.Reader
+	max, read int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.read > l.max {
+		return 0, &LimitError{"MaxInputBytes", l.max}
+	}
+	if remaining := l.max + 1 - l.read; int64(len(p)) > remaining { // read one more byte to detect the limit
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n - 1, &LimitError{"MaxInputBytes", l.max}
+	}
+	return n, err
+}
+
+func limitInput(r io.Reader, limits Limits) io.Reader {
+	if limits.MaxInputBytes <= 0 {
+		return r
+	}
+	return &limitedReader{r: r, max: limits.MaxInputBytes}
+}
+
+func (l Limits) checkBinary(size int) error {
+	if l.MaxBinaryBytes > 0 && size > l.MaxBinaryBytes {
+		return &LimitError{"MaxBinaryBytes", int64(l.MaxBinaryBytes)}
+	}
+	return nil
+}
+
+func (l Limits) checkDepth(depth int) error {
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return &LimitError{"MaxDepth", int64(l.MaxDepth)}
+	}
+	return nil
+}
+
+func (l Limits) checkControls(controls int) error {
+	if l.MaxControlWords > 0 && controls > l.MaxControlWords {
+		return &LimitError{"MaxControlWords", int64(l.MaxControlWords)}
+	}
+	return nil
+}
+
+// checkOutput returns an error when size bytes of output exceed the limit
+func (l Limits) checkOutput(size int) error {
+	if l.MaxOutputBytes > 0 && size > l.MaxOutputBytes {
+		return &LimitError{"MaxOutputBytes", int64(l.MaxOutputBytes)}
+	}
+	return nil
+}
+
+func (c *converter) checkControls() error {
+	c.controls++
+	return c.opts.Limits.checkControls(c.controls)
+}
+
+// checkOutput returns false once n more bytes of document text or captured
+// text would exceed the limit. The error is kept until the conversion loop
+// can return it
+func (c *converter) checkOutput(n int) bool {
+	if err := c.opts.Limits.checkOutput(c.text.Len() + c.captured + n); err != nil {
+		if c.err == nil {
+			c.err = err
+		}
+		return false
+	}
+	return true
+}
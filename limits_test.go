@@ -0,0 +1,49 @@
+package rtf2txt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		doc    string
+		limits Limits
+		limit  string
+	}{
+		{`{\rtf1\pard\f0 hello\par}`, Limits{MaxInputBytes: 10}, "MaxInputBytes"},
+		{`{\rtf1{\pict\bin6 abcdef}}`, Limits{MaxBinaryBytes: 5}, "MaxBinaryBytes"},
+		{`{\rtf1{{{\f0 deep}}}}`, Limits{MaxDepth: 3}, "MaxDepth"},
+		{`{\rtf1\pard\f0 hello there\par}`, Limits{MaxOutputBytes: 5}, "MaxOutputBytes"},
+		{`{\rtf1\pard\f0 hello\par}`, Limits{MaxControlWords: 3}, "MaxControlWords"},
+		{`{\rtf1{\*\annotation\pard\f0 hello there}}`, Limits{MaxOutputBytes: 5}, "MaxOutputBytes"},
+		{`{\rtf1{\pict\bin6 abcdef}}`, Limits{MaxOutputBytes: 5}, "MaxOutputBytes"},
+	}
+	for _, test := range tests {
+		_, err := TextWithOptions(strings.NewReader(test.doc), Options{Limits: test.limits})
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != test.limit {
+			t.Error("expected limit error", test.limit, err)
+		}
+	}
+
+	// exactly at the limits
+	const doc = `{\rtf1{\pict\bin5 abcde}\pard\f0 hello\par}`
+	limits := Limits{MaxInputBytes: int64(len(doc)), MaxBinaryBytes: 5, MaxDepth: 2, MaxOutputBytes: 11, MaxControlWords: 6}
+	if r, err := TextWithOptions(strings.NewReader(doc), Options{Limits: limits}); err != nil || r.String() != "hello " {
+		t.Error("expected success at the limits", err)
+	}
+
+	// the size of \bin doesn't decide how much is allocated
+	if _, err := Text(strings.NewReader(`{\rtf1{\pict\bin999999999999999 abc}}`)); err == nil {
+		t.Error("expected truncated binary data")
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	err := &LimitError{"MaxDepth", 10}
+	if err.Error() != "Document exceeds limit. MaxDepth is 10" {
+		t.Error("expected error message", err.Error())
+	}
+}
@@ -0,0 +1,206 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// starredDestinations are destinations which the RTF specification only
+// allows after \*, so that readers which don't know them skip their text.
+// Destinations which Word writes without \*, such as \mmathPr, are left out
+var starredDestinations = destinationsUsed(useStarred)
+
+// Lint is used to check the structure of the RTF data of an io.Reader. It
+// finds a missing {\rtf1 header, unbalanced braces, \binN longer than the
+// data after it, destinations which need \*, \fN which aren't in the font
+// table and \uN without the fallback characters set by \ucN. The findings
+// are sorted by offset. An error is only returned when r can't be read
+func Lint(r io.Reader) ([]Finding, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	l := &linter{data: data}
+	if !bytes.HasPrefix(data, []byte(`{\rtf1`)) {
+		header := data
+		if len(header) > len(`{\rtf1`) {
+			header = header[:len(`{\rtf1`)]
+		}
+		l.add(0, "document starts with %q instead of {\\rtf1", header)
+	}
+	l.lex()
+	l.braces()
+	l.destinations()
+	l.fonts()
+	l.unicode()
+	sort.SliceStable(l.findings, func(i, j int) bool { return l.findings[i].Offset < l.findings[j].Offset })
+	return l.findings, nil
+}
+
+// linter collects the findings of Lint
+type linter struct {
+	data      []byte
+	tokens    []token
+	truncated bool // the data after the tokens can't be lexed
+	findings  []Finding
+}
+
+func (l *linter) add(offset int64, format string, args ...interface{}) {
+	f := Finding{offset, fmt.Sprintf(format, args...)}
+	for _, existing := range l.findings {
+		if existing == f {
+			return
+		}
+	}
+	l.findings = append(l.findings, f)
+}
+
+// lex splits the data into tokens. The data after a \binN which is longer
+// than the data is lexed as if the \binN had no data, so that it is checked
+// too
+func (l *linter) lex() {
+	for offset := int64(0); offset < int64(len(l.data)); {
+		tokens, err := lex(l.data[offset:])
+		for i := range tokens {
+			tokens[i].start += offset
+			tokens[i].end += offset
+		}
+		l.tokens = append(l.tokens, tokens...)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			return
+		}
+		if last := len(l.tokens) - 1; len(tokens) > 0 && l.tokens[last].control == "binN" && l.tokens[last].num >= 0 {
+			bin := l.tokens[last]
+			l.add(bin.start, "\\bin%d is longer than the %d bytes after it", bin.num, int64(len(l.data))-bin.end)
+			offset = bin.end
+			continue
+		}
+		l.add(offset+parseErr.Offset, "document can't be parsed: %s", parseErr.message())
+		l.truncated = true
+		return
+	}
+}
+
+// braces finds unbalanced braces
+func (l *linter) braces() {
+	var open []int64
+	for _, t := range l.tokens {
+		switch t.kind {
+		case tokenGroupStart:
+			open = append(open, t.start)
+		case tokenGroupEnd:
+			if len(open) == 0 {
+				l.add(t.start, "closing brace without an open group")
+				continue
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if l.truncated { // the groups may be closed in the data which can't be lexed
+		return
+	}
+	for _, offset := range open {
+		l.add(offset, "group is not closed")
+	}
+}
+
+// destinations finds groups which should start with \*
+func (l *linter) destinations() {
+	data, tokens := l.data, l.tokens
+	for i, t := range tokens {
+		if t.kind != tokenGroupStart || i+1 >= len(tokens) || !tokens[i+1].isWord() {
+			continue
+		}
+		if t.start == 0 { // the header of the document is checked by Lint
+			continue
+		}
+		first := tokens[i+1]
+		name := strings.TrimSpace(string(data[first.start:first.end]))
+		if starredDestinations[first.control] {
+			l.add(first.start, "%s must be preceded by \\*", name)
+		} else if known, _ := knownControl(first.control); !known {
+			l.add(first.start, "unknown %s starts a group without \\*, so its text is shown", name)
+		}
+	}
+}
+
+// fonts finds fonts which aren't in the font table
+func (l *linter) fonts() {
+	tokens := l.tokens
+	ends := groupEnds(tokens)
+	fonts := make(map[int]bool)
+	var uses []token
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind == tokenGroupStart {
+			if dest, _ := groupDest(tokens, i); dest == "fonttbl" {
+				for j := i + 1; j < ends[i] && j < len(tokens); j++ {
+					if tokens[j].control == "fN" {
+						fonts[tokens[j].num] = true
+					}
+				}
+				i = ends[i]
+			}
+			continue
+		}
+		if t.control == "fN" || t.control == "deffN" {
+			uses = append(uses, t)
+		}
+	}
+	reported := make(map[int]bool)
+	for _, t := range uses {
+		if !fonts[t.num] && !reported[t.num] {
+			reported[t.num] = true
+			l.add(t.start, "\\f%d is not in the font table", t.num)
+		}
+	}
+}
+
+// unicode finds \uN without enough fallback characters
+func (l *linter) unicode() {
+	data, tokens := l.data, l.tokens
+	ucs := []int{1} // \ucN of each group
+	for i, t := range tokens {
+		switch {
+		case t.kind == tokenGroupStart:
+			ucs = append(ucs, ucs[len(ucs)-1])
+		case t.kind == tokenGroupEnd && len(ucs) > 1:
+			ucs = ucs[:len(ucs)-1]
+		case t.control == "ucN":
+			ucs[len(ucs)-1] = t.num
+		case t.control == "uN":
+			uc := ucs[len(ucs)-1]
+			if found := fallbackChars(data, tokens[i+1:], uc); found < uc {
+				l.add(t.start, "\\u%d is followed by %d fallback characters instead of %d", t.num, found, uc)
+			}
+		}
+	}
+}
+
+// fallbackChars counts up to uc fallback characters at the start of tokens
+func fallbackChars(data []byte, tokens []token, uc int) int {
+	found := 0
+	for _, t := range tokens {
+		if found >= uc {
+			break
+		}
+		switch {
+		case t.kind == tokenText:
+			for o := t.start; o < t.end && found < uc; o++ {
+				if data[o] != '\r' && data[o] != '\n' {
+					found++
+				}
+			}
+		case t.kind == tokenControl && (t.control[0] == '\'' || t.control == "\\" || t.control == "{" || t.control == "}"):
+			found++
+		default:
+			return found
+		}
+	}
+	return found
+}
@@ -0,0 +1,61 @@
+package rtf2txt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		in       string
+		findings []Finding
+	}{
+		{`{\rtf1{\fonttbl{\f0 Arial;}}\f0 caf\u233\'e9 {\uc2\u8364 EU}\par}`, nil},
+		{`{\rtf1\deff1{\fonttbl{\f0 Arial;}}\f0 a\f2 b\f2 c}`, []Finding{{6, `\f1 is not in the font table`}, {39, `\f2 is not in the font table`}}},
+		{`{\rtf1 a}}{b`, []Finding{{9, "closing brace without an open group"}, {10, "group is not closed"}}},
+		{`{\rtf a}`, []Finding{{0, `document starts with "{\\rtf " instead of {\rtf1`}}},
+		{`{\rtf1{\rtf a}}`, []Finding{{7, `unknown \rtf starts a group without \*, so its text is shown`}}},
+		{`{\rtf1 a\`, []Finding{{9, "document can't be parsed: Unexpected end of RTF data"}}},
+		{`{\rtf1 {\generator x}{\*\generator y}{\foo z}}`, []Finding{{8, `\generator must be preceded by \*`}, {38, `unknown \foo starts a group without \*, so its text is shown`}}},
+		{`{\rtf1 \u233\par{\uc2\u8364 ?}}`, []Finding{{7, `\u233 is followed by 0 fallback characters instead of 1`}, {21, `\u8364 is followed by 1 fallback characters instead of 2`}}},
+		{`{\rtf1 \bin10 abc}`, []Finding{{7, `\bin10 is longer than the 4 bytes after it`}}},
+		{`{\rtf1 \bin30 a{\f3 b}\u233}}`, []Finding{{7, `\bin30 is longer than the 15 bytes after it`},
+			{16, `\f3 is not in the font table`}, {22, `\u233 is followed by 0 fallback characters instead of 1`},
+			{28, "closing brace without an open group"}}},
+	}
+	for _, test := range tests {
+		findings, err := Lint(strings.NewReader(test.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(findings) != len(test.findings) {
+			t.Errorf("expected %v for %q, got %v", test.findings, test.in, findings)
+			continue
+		}
+		for i, f := range findings {
+			if f != test.findings[i] {
+				t.Errorf("expected %v for %q, got %v", test.findings[i], test.in, f)
+			}
+		}
+	}
+}
+
+func TestLintTestdata(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.rtf")
+	if err != nil || len(files) == 0 {
+		t.Fatal("expected test documents", err)
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		findings, err := Lint(f)
+		f.Close()
+		if err != nil || len(findings) != 0 {
+			t.Error("expected no findings", name, err, findings)
+		}
+	}
+}
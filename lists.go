@@ -0,0 +1,314 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// listTable holds the lists defined in \listtable and \listoverridetable
+// along with the counters needed to number list paragraphs
+type listTable struct {
+	lists     []*list
+	overrides []*listOverride
+	counters  map[int]*listCounters // current number of each level, by \lsN
+
+	// definitions being read
+	list     *list
+	level    *listLevel
+	override *listOverride
+}
+
+type list struct {
+	id     int
+	levels []*listLevel
+}
+
+type listLevel struct {
+	nfc       int    // \levelnfcN number format
+	startAt   int    // \levelstartatN
+	follow    int    // \levelfollowN. 0 is a tab, 1 is a space and 2 is nothing
+	legal     bool   // \levellegalN. Numbers from other levels are shown in decimal
+	noRestart bool   // \levelnorestartN. Not restarted when a higher level is used
+	text      string // \leveltext without its length prefix
+}
+
+// listCounters are the numbers of the levels of a list override. A level
+// is started by its first paragraph, as its start can be 0
+type listCounters struct {
+	value   [maxLevelPlaceholder]int
+	started [maxLevelPlaceholder]bool
+}
+
+type listOverride struct {
+	ls      int
+	listID  int
+	startAt map[int]int // \listoverridestartat values, by level
+	lfo     int         // number of \lfolevel groups read
+}
+
+// Level numbering formats used by \levelnfcN
+const (
+	nfcDecimal     = 0
+	nfcUpperRoman  = 1
+	nfcLowerRoman  = 2
+	nfcUpperLetter = 3
+	nfcLowerLetter = 4
+	nfcOrdinal     = 5
+	nfcDecimalZero = 22
+	nfcBullet      = 23
+	nfcNone        = 255
+)
+
+// maxLevelPlaceholder is one more than the highest level placeholder
+// in \leveltext. It is also the maximum number of list levels
+const maxLevelPlaceholder = 9
+
+func (t *listTable) handleControl(c *converter, control string, num int) {
+	g := c.group()
+	switch control {
+	case "list":
+		if g.dest != destListTable {
+			return
+		}
+		t.list = &list{}
+		t.lists = append(t.lists, t.list)
+		g.dest = destList
+	case "listlevel":
+		if g.dest != destList {
+			return
+		}
+		t.level = &listLevel{startAt: 1}
+		t.list.levels = append(t.list.levels, t.level)
+		g.dest = destListLevel
+	case "listidN":
+		switch g.dest {
+		case destList:
+			t.list.id = num
+		case destListOverride:
+			t.override.listID = num
+		}
+	case "levelnfcN", "levelnfcnN":
+		if g.dest == destListLevel {
+			t.level.nfc = num
+		}
+	case "levelstartatN":
+		switch g.dest {
+		case destListLevel:
+			t.level.startAt = num
+		case destLfoLevel:
+			t.override.startAt[t.override.lfo-1] = num
+		}
+	case "levelfollowN":
+		if g.dest == destListLevel {
+			t.level.follow = num
+		}
+	case "levellegalN":
+		if g.dest == destListLevel {
+			t.level.legal = num != 0
+		}
+	case "levelnorestartN":
+		if g.dest == destListLevel {
+			t.level.noRestart = num != 0
+		}
+	case "leveltext":
+		if g.dest != destListLevel {
+			return
+		}
+		level := t.level
+		c.capture(destListLevel, true, func(text string) { level.text = levelText(text) })
+	case "listoverride":
+		if g.dest != destListOverrideTable {
+			return
+		}
+		t.override = &listOverride{startAt: make(map[int]int)}
+		t.overrides = append(t.overrides, t.override)
+		g.dest = destListOverride
+	case "lfolevel":
+		if g.dest != destListOverride {
+			return
+		}
+		t.override.lfo++
+		g.dest = destLfoLevel
+	}
+}
+
+// levelText removes the length prefix from \leveltext. What is left contains
+// the literal text of the number along with placeholders ('\x00' to '\x08')
+// for the number of each level
+func levelText(text string) string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return ""
+	}
+	size := int(runes[0])
+	if size > len(runes)-1 {
+		size = len(runes) - 1
+	}
+	return string(runes[1 : size+1])
+}
+
+// number advances the counters of list override ls and returns the number
+// Word displays for a paragraph at level ilvl, including the text that
+// follows it
+func (t *listTable) number(ls, ilvl int) (string, bool) {
+	o := t.findOverride(ls)
+	if o == nil {
+		return "", false
+	}
+	l := t.findList(o.listID)
+	if l == nil || len(l.levels) == 0 {
+		return "", false
+	}
+	if ilvl < 0 || ilvl >= len(l.levels) || ilvl >= maxLevelPlaceholder {
+		ilvl = 0
+	}
+
+	if t.counters == nil {
+		t.counters = make(map[int]*listCounters)
+	}
+	counters, ok := t.counters[ls]
+	if !ok {
+		counters = &listCounters{}
+		t.counters[ls] = counters
+	}
+	if counters.started[ilvl] {
+		counters.value[ilvl]++
+	} else {
+		counters.value[ilvl], counters.started[ilvl] = o.start(l, ilvl), true
+	}
+	for i := ilvl + 1; i < len(l.levels) && i < maxLevelPlaceholder; i++ {
+		if !l.levels[i].noRestart {
+			counters.started[i] = false
+		}
+	}
+
+	level := l.levels[ilvl]
+	if level.nfc == nfcNone {
+		return "", true
+	}
+	var buf bytes.Buffer
+	for _, r := range level.text {
+		if r >= maxLevelPlaceholder {
+			if level.nfc == nfcBullet && r >= 0xF000 && r <= 0xF0FF { // symbol font bullets
+				r = '*'
+			}
+			buf.WriteRune(r)
+			continue
+		}
+		i := int(r)
+		if i >= len(l.levels) {
+			continue
+		}
+		value := counters.value[i]
+		if !counters.started[i] {
+			value = o.start(l, i)
+		}
+		nfc := l.levels[i].nfc
+		if level.legal && i != ilvl {
+			nfc = nfcDecimal
+		}
+		buf.WriteString(formatNumber(value, nfc))
+	}
+	if level.follow != 2 {
+		buf.WriteByte(' ')
+	}
+	return buf.String(), true
+}
+
+func (t *listTable) findOverride(ls int) *listOverride {
+	for _, o := range t.overrides {
+		if o.ls == ls {
+			return o
+		}
+	}
+	return nil
+}
+
+func (t *listTable) findList(id int) *list {
+	for _, l := range t.lists {
+		if l.id == id {
+			return l
+		}
+	}
+	return nil
+}
+
+func (o *listOverride) start(l *list, ilvl int) int {
+	if start, ok := o.startAt[ilvl]; ok {
+		return start
+	}
+	return l.levels[ilvl].startAt
+}
+
+func formatNumber(n, nfc int) string {
+	switch nfc {
+	case nfcUpperRoman:
+		return strings.ToUpper(roman(n))
+	case nfcLowerRoman:
+		return roman(n)
+	case nfcUpperLetter:
+		return strings.ToUpper(letter(n))
+	case nfcLowerLetter:
+		return letter(n)
+	case nfcOrdinal:
+		return strconv.Itoa(n) + ordinalSuffix(n)
+	case nfcDecimalZero:
+		if n < 10 && n >= 0 {
+			return "0" + strconv.Itoa(n)
+		}
+		return strconv.Itoa(n)
+	case nfcBullet, nfcNone:
+		return ""
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+// maxRoman and maxLetter are the highest numbers written as roman numerals
+// and letters. Higher numbers are written in decimal rather than as
+// strings as long as the number
+const (
+	maxRoman  = 3999
+	maxLetter = 780 // zz...z with 30 letters, like Word
+)
+
+func roman(n int) string {
+	if n <= 0 || n > maxRoman {
+		return strconv.Itoa(n)
+	}
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	numerals := []string{"m", "cm", "d", "cd", "c", "xc", "l", "xl", "x", "ix", "v", "iv", "i"}
+	var buf bytes.Buffer
+	for i, v := range values {
+		for n >= v {
+			buf.WriteString(numerals[i])
+			n -= v
+		}
+	}
+	return buf.String()
+}
+
+// letter returns the alphabetic number the way Word does: a to z, then aa
+// to zz and so on
+func letter(n int) string {
+	if n <= 0 || n > maxLetter {
+		return strconv.Itoa(n)
+	}
+	return strings.Repeat(string(rune('a'+(n-1)%26)), (n-1)/26+1)
+}
+
+func ordinalSuffix(n int) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+		return "th"
+	case n%10 == 1:
+		return "st"
+	case n%10 == 2:
+		return "nd"
+	case n%10 == 3:
+		return "rd"
+	default:
+		return "th"
+	}
+}
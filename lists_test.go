@@ -0,0 +1,78 @@
+package rtf2txt
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestListNumbering(t *testing.T) {
+	f, _ := os.Open(`testdata/list.rtf`)
+	r, err := Text(f)
+	f.Close()
+	expected := "Intro 1. First a) Sub one iv. Deep b) Sub two 2. Second a) Sub again * Bullet Done "
+	if err != nil || r.String() != expected {
+		t.Error("expected computed list numbers", err, r.String())
+	}
+
+	// no list table, so the cached list text is used
+	r, err = Text(strings.NewReader(`{\rtf1{\pntext\f0 3.\tab}\pard\f0 Cached\par}`))
+	if err != nil || r.String() != "3. Cached " {
+		t.Error("expected cached list text", err, r.String())
+	}
+
+	// empty list paragraphs still get a number
+	r, err = Text(strings.NewReader(`{\rtf1{\*\listtable{\list{\listlevel\levelnfc3\levelstartat1{\leveltext\'02\'00.;}}\listid5}}` +
+		`{\*\listoverridetable{\listoverride\listid5{\lfolevel\listoverridestartat\levelstartat3}\ls1}}\pard\ls1\par\pard\ls1\f0 Next\par}`))
+	if err != nil || r.String() != "C.  D. Next " {
+		t.Error("expected number for empty paragraph", err, r.String())
+	}
+
+	// levels can start at 0, also when they are restarted
+	r, err = Text(strings.NewReader(`{\rtf1{\*\listtable{\list{\listlevel\levelstartat0{\leveltext\'02\'00.;}}` +
+		`{\listlevel\levelstartat0{\leveltext\'02\'01);}}\listid7}}{\*\listoverridetable{\listoverride\listid7\ls1}}` +
+		`\pard\ls1\f0 a\par\pard\ls1\ilvl1\f0 b\par\pard\ls1\ilvl1\f0 c\par\pard\ls1\f0 d\par\pard\ls1\ilvl1\f0 e\par}`))
+	if err != nil || r.String() != "0. a 0) b 1) c 1. d 0) e " {
+		t.Error("expected numbers starting at 0", err, r.String())
+	}
+}
+
+func TestLevelText(t *testing.T) {
+	if s := levelText("\x02\x00.;"); s != "\x00." {
+		t.Error("expected prefix removed", s)
+	}
+	if s := levelText("\x05\x00."); s != "\x00." {
+		t.Error("expected truncated text", s)
+	}
+	if s := levelText(""); s != "" {
+		t.Error("expected empty text", s)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n, nfc   int
+		expected string
+	}{
+		{3, nfcDecimal, "3"},
+		{4, nfcUpperRoman, "IV"},
+		{1994, nfcLowerRoman, "mcmxciv"},
+		{2, nfcUpperLetter, "B"},
+		{28, nfcLowerLetter, "bb"},
+		{1, nfcOrdinal, "1st"},
+		{12, nfcOrdinal, "12th"},
+		{23, nfcOrdinal, "23rd"},
+		{7, nfcDecimalZero, "07"},
+		{1, nfcBullet, ""},
+		{1, nfcNone, ""},
+		{5, 60, "5"},
+		{4000, nfcUpperRoman, "4000"},
+		{780, nfcLowerLetter, strings.Repeat("z", 30)},
+		{2000000000, nfcLowerLetter, "2000000000"},
+	}
+	for _, test := range tests {
+		if s := formatNumber(test.n, test.nfc); s != test.expected {
+			t.Error("expected formatted number", test.n, test.nfc, s)
+		}
+	}
+}
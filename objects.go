@@ -0,0 +1,231 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Object is an OLE object found in an \object destination
+type Object struct {
+	// Class is the class name from \objclass or, when that is missing, from
+	// the OLE1 header of \objdata
+	Class string
+
+	// Topic and Item are from the OLE1 header. For embedded objects the
+	// topic is usually the same as the class
+	Topic, Item string
+
+	Linked   bool   // \objdata holds a linked rather than an embedded object
+	Update   bool   // \objupdate. The object is updated when the document is opened
+	AutoLink bool   // \objautlink. The link is updated automatically
+	Data     []byte // decoded \objdata
+	Native   []byte // native data of an embedded object
+
+	// FileName, SourcePath and Contents are the original file of a
+	// "Package" object, which is how files are attached to documents
+	FileName   string
+	SourcePath string
+	Contents   []byte
+
+	// Result is the text of the \result destination, which is what Word
+	// displays for the object. It is also part of the document text
+	Result string
+
+	resultStart, resultEnd int
+
+	// details of the object data for Analyze
+	offset      int64
+	err         error  // error parsing the OLE1 data
+	version     uint32 // OLE1 version
+	headerClass string // class name from the OLE1 header
+	trailing    int    // bytes following the OLE1 data
+	junk        int    // characters in \objdata that aren't hex digits
+}
+
+// OLE1 format IDs from the header of \objdata
+const (
+	oleLinked   = 1
+	oleEmbedded = 2
+)
+
+var errShortOLE = errors.New("OLE1 data is truncated")
+
+// Objects is used to get the OLE objects from an io.Reader containing RTF
+// data
+func Objects(r io.Reader) ([]Object, error) {
+	d, err := Convert(r, Options{})
+	if err != nil {
+		return nil, err
+	}
+	return d.Objects, nil
+}
+
+// handleObject updates the object being read for the \object destination
+// and its content
+func (c *converter) handleObject(control string) {
+	if control == "object" {
+		c.objects = append(c.objects, Object{resultStart: -1, offset: c.start})
+		i := len(c.objects) - 1
+		c.capture(destObject, false, func(string) { c.objects[i].err = c.objects[i].parseOLE1() })
+		return
+	}
+	if c.group().dest != destObject {
+		return
+	}
+	i := len(c.objects) - 1 // objects can be nested in \result, so don't hold a pointer
+	switch control {
+	case "objclass":
+		c.capture(destObject, true, func(text string) { c.objects[i].Class = text })
+	case "objdata":
+		c.capture(destObject, true, func(text string) {
+			c.objects[i].Data = decodeHex(text)
+			for j := 0; j < len(text); j++ {
+				if !isHexDigit(text[j]) {
+					c.objects[i].junk++
+				}
+			}
+		})
+	case "objupdate":
+		c.objects[i].Update = true
+	case "objautlink":
+		c.objects[i].AutoLink = true
+	case "result":
+		c.objects[i].resultStart = c.runes
+		g := c.group()
+		g.out, g.raw, g.dest = nil, false, destText // the result is displayed, so it is document text
+		g.done = func(string) { c.objects[i].resultEnd = c.runes }
+	}
+}
+
+// anchorObjects sets the result text of each object once all of the
+// document text is known
+func (c *converter) anchorObjects(text []rune) {
+	for i := range c.objects {
+		o := &c.objects[i]
+		if o.resultStart < 0 || o.resultEnd < o.resultStart || o.resultEnd > len(text) {
+			continue
+		}
+		o.Result = string(text[o.resultStart:o.resultEnd])
+	}
+}
+
+// parseOLE1 reads the OLE1 header of the object data followed by the native
+// data of an embedded object. Package objects are unwrapped to the file
+// they contain
+func (o *Object) parseOLE1() error {
+	r := bytes.NewReader(o.Data)
+	var version, format uint32
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return errShortOLE
+	}
+	o.version = version
+	if err := binary.Read(r, binary.LittleEndian, &format); err != nil {
+		return errShortOLE
+	}
+	if format != oleLinked && format != oleEmbedded {
+		return errors.New("unknown OLE1 format")
+	}
+	o.Linked = format == oleLinked
+
+	var class string
+	var err error
+	if class, err = readLengthPrefixed(r); err != nil {
+		return err
+	}
+	if o.Topic, err = readLengthPrefixed(r); err != nil {
+		return err
+	}
+	if o.Item, err = readLengthPrefixed(r); err != nil {
+		return err
+	}
+	o.headerClass = class
+	if o.Class == "" {
+		o.Class = class
+	}
+	if o.Linked {
+		return nil
+	}
+
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return errShortOLE
+	}
+	if int64(size) > int64(r.Len()) {
+		return errShortOLE
+	}
+	o.Native = make([]byte, size)
+	r.Read(o.Native)
+	o.trailing = r.Len()
+	if class == "Package" {
+		return o.parsePackage()
+	}
+	return nil
+}
+
+// parsePackage reads the file name, source path and contents of the native
+// data of a Package object
+func (o *Object) parsePackage() error {
+	r := bytes.NewReader(o.Native)
+	var header uint16
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return errShortOLE
+	}
+	var err error
+	if o.FileName, err = readZeroTerminated(r); err != nil {
+		return err
+	}
+	if o.SourcePath, err = readZeroTerminated(r); err != nil {
+		return err
+	}
+	var kind, tempSize uint32
+	if err := binary.Read(r, binary.LittleEndian, &kind); err != nil {
+		return errShortOLE
+	}
+	if err := binary.Read(r, binary.LittleEndian, &tempSize); err != nil {
+		return errShortOLE
+	}
+	if _, err := readZeroTerminated(r); err != nil { // temporary path
+		return err
+	}
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return errShortOLE
+	}
+	if int64(size) > int64(r.Len()) {
+		return errShortOLE
+	}
+	o.Contents = make([]byte, size)
+	r.Read(o.Contents)
+	return nil
+}
+
+// readLengthPrefixed reads an OLE1 string which starts with its length,
+// including the terminating zero
+func readLengthPrefixed(r *bytes.Reader) (string, error) {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return "", errShortOLE
+	}
+	if int64(size) > int64(r.Len()) {
+		return "", errShortOLE
+	}
+	s := make([]byte, size)
+	r.Read(s)
+	return string(bytes.TrimRight(s, "\x00")), nil
+}
+
+func readZeroTerminated(r *bytes.Reader) (string, error) {
+	var buf bytes.Buffer
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", errShortOLE
+		}
+		if b == 0 {
+			return buf.String(), nil
+		}
+		buf.WriteByte(b)
+	}
+}
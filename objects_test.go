@@ -0,0 +1,67 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// ole1 builds OLE1 object data for an embedded object
+func ole1(class string, native []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(0x0501))
+	binary.Write(&buf, binary.LittleEndian, uint32(oleEmbedded))
+	for _, s := range []string{class, "", ""} {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(s)+1))
+		buf.WriteString(s + "\x00")
+	}
+	binary.Write(&buf, binary.LittleEndian, uint32(len(native)))
+	buf.Write(native)
+	return buf.Bytes()
+}
+
+func packageData(name, path string, contents []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint16(2))
+	buf.WriteString(name + "\x00" + path + "\x00")
+	binary.Write(&buf, binary.LittleEndian, uint32(0x00030000))
+	binary.Write(&buf, binary.LittleEndian, uint32(len(path)+1))
+	buf.WriteString(path + "\x00")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(contents)))
+	buf.Write(contents)
+	return buf.Bytes()
+}
+
+func TestObjects(t *testing.T) {
+	pkg := hex.EncodeToString(ole1("Package", packageData("report.pdf", `C:\docs\report.pdf`, []byte("%PDF-1.4"))))
+	sheet := hex.EncodeToString(ole1("Excel.Sheet.8", []byte{1, 2, 3}))
+	doc := `{\rtf1\pard\f0 See {\object\objemb\objw100\objh50{\*\objdata ` + pkg[:40] + "\n" + pkg[40:] + `}` +
+		`{\result {\f0 report.pdf}}}\f0  and {\object\objemb{\*\objclass Excel.Sheet.12}{\*\objdata ` + sheet + `}}\par}`
+	d, err := Convert(strings.NewReader(doc), Options{})
+	if err != nil || len(d.Objects) != 2 {
+		t.Fatal("expected objects", err, d)
+	}
+	if d.Text != "See report.pdf and  " {
+		t.Error("expected result in text", d.Text)
+	}
+	o := d.Objects[0]
+	if o.Class != "Package" || o.Linked || o.FileName != "report.pdf" || o.SourcePath != `C:\docs\report.pdf` ||
+		string(o.Contents) != "%PDF-1.4" || o.Result != "report.pdf" {
+		t.Error("expected package object", o)
+	}
+	o = d.Objects[1]
+	if o.Class != "Excel.Sheet.12" || !bytes.Equal(o.Native, []byte{1, 2, 3}) || o.FileName != "" || o.Result != "" {
+		t.Error("expected excel object", o)
+	}
+
+	o = Object{Data: ole1("Package", []byte{2, 0, 'a'})}
+	if err := o.parseOLE1(); err != errShortOLE || o.Class != "Package" {
+		t.Error("expected truncated package", err, o)
+	}
+	o = Object{Data: []byte{1, 5, 0, 0, 9, 0, 0, 0}}
+	if err := o.parseOLE1(); err == nil {
+		t.Error("expected unknown format")
+	}
+}
@@ -0,0 +1,116 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/EndFirstCorp/peekingReader"
+)
+
+// checkInterval is how many bytes are read between checks of the context
+const checkInterval = 4096
+
+// positionReader is a peekingReader.Reader which keeps track of the offset,
+// line and column of the next byte to be read. Once its context is done,
+// reads return the error of the context
+type positionReader struct {
+	peekingReader.Reader
+	ctx          context.Context
+	checkAt      int64 // offset at which the context is checked next
+	offset       int64
+	line, column int
+}
+
+func newPositionReader(r peekingReader.Reader) *positionReader {
+	return &positionReader{Reader: r, ctx: context.Background(), checkAt: checkInterval, line: 1, column: 1}
+}
+
+// checkContext returns the error of the context every checkInterval bytes
+func (p *positionReader) checkContext() error {
+	if p.offset < p.checkAt {
+		return nil
+	}
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	p.checkAt = p.offset + checkInterval
+	return nil
+}
+
+// advance moves the position past b
+func (p *positionReader) advance(b []byte) {
+	p.offset += int64(len(b))
+	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+		p.line += bytes.Count(b, []byte{'\n'})
+		p.column = len(b) - i
+		return
+	}
+	p.column += len(b)
+}
+
+func (p *positionReader) ReadByte() (byte, error) {
+	if err := p.checkContext(); err != nil {
+		return 0, err
+	}
+	b, err := p.Reader.ReadByte()
+	if err == nil {
+		p.advance([]byte{b})
+	}
+	return b, err
+}
+
+func (p *positionReader) ReadBytes(size int) ([]byte, error) {
+	if err := p.checkContext(); err != nil {
+		return nil, err
+	}
+	b, err := p.Reader.ReadBytes(size)
+	p.advance(b)
+	return b, err
+}
+
+func (p *positionReader) ReadRune() (rune, int, error) {
+	if err := p.checkContext(); err != nil {
+		return 0, 0, err
+	}
+	r, size, err := p.Reader.ReadRune()
+	p.offset += int64(size)
+	if r == '\n' {
+		p.line++
+		p.column = 1
+	} else {
+		p.column += size
+	}
+	return r, size, err
+}
+
+func (p *positionReader) Read(b []byte) (int, error) {
+	if err := p.checkContext(); err != nil {
+		return 0, err
+	}
+	if max := p.checkAt - p.offset; max > 0 && int64(len(b)) > max { // large reads such as \binN data are checked too
+		b = b[:max]
+	}
+	n, err := p.Reader.Read(b)
+	if n > 0 {
+		p.advance(b[:n])
+	}
+	return n, err
+}
+
+// offset returns the offset of the next byte to be read, or -1 when the
+// reader doesn't keep track of its position
+func (c *converter) offset() int64 {
+	if p, ok := c.r.(*positionReader); ok {
+		return p.offset
+	}
+	return -1
+}
+
+// position returns the line and column of the next byte to be read, or 0, 0
+// when the reader doesn't keep track of its position
+func (c *converter) position() (int, int) {
+	if p, ok := c.r.(*positionReader); ok {
+		return p.line, p.column
+	}
+	return 0, 0
+}
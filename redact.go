@@ -0,0 +1,222 @@
+package rtf2txt
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// dataDestinations are the destinations whose text is hex encoded data which
+// can contain text of its own
+var dataDestinations = destinationsUsed(useData)
+
+// redactedDestinations are the destinations besides the text of the
+// document whose text Redact searches. The text of other destinations, such
+// as font names, bookmark names and hex encoded properties, is left alone so
+// that the RTF stays valid
+var redactedDestinations = destinationsUsed(useRedacted)
+
+// bookmarkFields are the fields whose first argument is the name of a
+// bookmark
+var bookmarkFields = map[string]bool{"REF": true, "PAGEREF": true, "NOTEREF": true}
+
+// RedactOptions are what Redact masks
+type RedactOptions struct {
+	Patterns []*regexp.Regexp // matches of these are masked
+	Strings  []string         // occurrences of these are masked
+	Mask     rune             // written for each character of text, '█' when 0
+}
+
+// Redact is used to copy RTF from an io.Reader to an io.Writer, masking text
+// which matches the options. Each character matched is replaced by the mask,
+// so that the structure and formatting of the document are kept. Besides the
+// text of the document, including hidden text, headers, footers and
+// footnotes, the text of \info, comments and field instructions is
+// searched. Other destinations such as the font table are not, and neither
+// are the names of bookmarks or the names REF, PAGEREF and NOTEREF fields
+// refer to, so that the references keep working. The text which is
+// bookmarked is searched with the rest of the document. The hex
+// encoded data of \*\datastore and \objdata is searched as ISO-8859-1 and
+// UTF-16 text, with each byte matched replaced by X. The number of matches
+// masked is returned
+func Redact(w io.Writer, r io.Reader, opts RedactOptions) (int, error) {
+	patterns := opts.Patterns
+	for _, s := range opts.Strings {
+		if s != "" {
+			patterns = append(patterns, regexp.MustCompile(regexp.QuoteMeta(s)))
+		}
+	}
+	mask := opts.Mask
+	if mask == 0 {
+		mask = '█'
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	tokens, err := lex(data)
+	if err != nil {
+		return 0, err
+	}
+	chars := textChars(data, tokens, func(dest string) textMode {
+		switch {
+		case dataDestinations[dest]:
+			return textData
+		case dest == "" || textDestinations[dest] || redactedDestinations[dest]:
+			return textRead
+		default:
+			return textSkip
+		}
+	})
+
+	names := bookmarkNames(chars)
+	masked := make(map[int]rune) // value written for each index of chars masked
+	count := 0
+	var text []int // indexes of chars of text
+	for i := 0; i < len(chars); i++ {
+		if !chars[i].data {
+			text = append(text, i)
+			continue
+		}
+		start := i // a run of data
+		for i < len(chars) && chars[i].data {
+			i++
+		}
+		count += redactData(chars, start, i, patterns, masked)
+		i--
+	}
+	runes := make([]rune, len(text))
+	for i, c := range text {
+		runes[i] = chars[c].r
+		if chars[c].fixed {
+			runes[i] = '\n' // matches of . don't span breaks
+		}
+	}
+	for _, m := range findRunes(runes, patterns) {
+		found := false
+		for _, c := range text[m[0]:m[1]] {
+			if !names[c] {
+				masked[c], found = mask, true
+			}
+		}
+		if found {
+			count++
+		}
+	}
+
+	var edits []edit
+	for i, value := range masked {
+		c := chars[i]
+		switch {
+		case c.fixed:
+		case !c.data:
+			edits = append(edits, c.rewrite(string(value)))
+		case c.end-c.start == 1: // binary data
+			edits = append(edits, edit{c.start, c.end, string(byte(value))})
+		default:
+			edits = append(edits, edit{c.start, c.end, fmt.Sprintf("%02x", value)})
+		}
+	}
+	return count, writeEdits(w, data, edits)
+}
+
+// bookmarkNames returns the indexes of chars which name the bookmark in
+// the instructions of REF, PAGEREF and NOTEREF fields
+func bookmarkNames(chars []textChar) map[int]bool {
+	names := make(map[int]bool)
+	for i := 0; i < len(chars); i++ {
+		if chars[i].dest != "fldinst" {
+			continue
+		}
+		var words [][2]int // start and end indexes of the first two words
+		for ; i < len(chars) && chars[i].dest == "fldinst" && !chars[i].fixed; i++ {
+			switch {
+			case unicode.IsSpace(chars[i].r):
+			case len(words) > 0 && words[len(words)-1][1] == i:
+				words[len(words)-1][1] = i + 1
+			case len(words) < 2:
+				words = append(words, [2]int{i, i + 1})
+			}
+		}
+		if len(words) == 2 && bookmarkFields[strings.ToUpper(fieldWord(chars, words[0]))] {
+			for j := words[1][0]; j < words[1][1]; j++ {
+				names[j] = true
+			}
+		}
+	}
+	return names
+}
+
+// fieldWord returns the text of the chars in the range
+func fieldWord(chars []textChar, word [2]int) string {
+	runes := make([]rune, word[1]-word[0])
+	for i := range runes {
+		runes[i] = chars[word[0]+i].r
+	}
+	return string(runes)
+}
+
+// redactData masks the matches in the bytes of chars[start:end] read as
+// ISO-8859-1 and as UTF-16 at both alignments
+func redactData(chars []textChar, start, end int, patterns []*regexp.Regexp, masked map[int]rune) int {
+	count := 0
+	latin1 := make([]rune, end-start)
+	for i := range latin1 {
+		latin1[i] = chars[start+i].r
+	}
+	for _, m := range findRunes(latin1, patterns) {
+		count++
+		for i := m[0]; i < m[1]; i++ {
+			masked[start+i] = 'X'
+		}
+	}
+	for align := 0; align < 2; align++ {
+		units := make([]uint16, (end-start-align)/2)
+		for i := range units {
+			units[i] = uint16(chars[start+align+2*i].r) | uint16(chars[start+align+2*i+1].r)<<8
+		}
+		runes := make([]rune, len(units)) // surrogates are replaced so that each rune is one unit
+		for i, u := range units {
+			runes[i] = rune(u)
+			if utf16.IsSurrogate(runes[i]) {
+				runes[i] = unicode.ReplacementChar
+			}
+		}
+		for _, m := range findRunes(runes, patterns) {
+			count++
+			for i := m[0]; i < m[1]; i++ {
+				masked[start+align+2*i], masked[start+align+2*i+1] = 'X', 0
+			}
+		}
+	}
+	return count
+}
+
+// findRunes returns the start and end indexes of the matches of the patterns
+// in runes
+func findRunes(runes []rune, patterns []*regexp.Regexp) [][2]int {
+	if len(runes) == 0 || len(patterns) == 0 {
+		return nil
+	}
+	s := string(runes)
+	index := make([]int, len(s)+1) // index of the rune at each byte offset
+	offset := 0
+	for i, r := range runes {
+		index[offset] = i
+		offset += len(string(r))
+	}
+	index[len(s)] = len(runes)
+
+	var matches [][2]int
+	for _, p := range patterns {
+		for _, m := range p.FindAllStringIndex(s, -1) {
+			if m[1] > m[0] {
+				matches = append(matches, [2]int{index[m[0]], index[m[1]]})
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,70 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	in := `{\rtf1\ansi{\info{\author John Smith}}Dear John,{\v John}{\*\bkmkstart John}John{\*\bkmkend John}` +
+		`{\field{\*\fldinst HYPERLINK "mailto:john@example.com"}{\fldrslt mail {\b Jo}hn}}{\*\annotation John}` +
+		`{\field{\*\fldinst {REF} John \\h}{\fldrslt John}}{\field{\*\fldinst PAGEREF John Smith}}` +
+		`{\*\datastore 004a6f686e00}{\object{\*\objdata 4a006f00` + "\r\n" + `68006e00}}{\pict 4a6f686e}\par}`
+	expected := `{\rtf1\ansi{\info{\author XXXX XXXXX}}Dear XXXX,{\v XXXX}{\*\bkmkstart John}XXXX{\*\bkmkend John}` +
+		`{\field{\*\fldinst HYPERLINK "mailto:XXXXXXXXXXXXXXXX"}{\fldrslt mail {\b XX}XX}}{\*\annotation XXXX}` +
+		`{\field{\*\fldinst {REF} John \\h}{\fldrslt XXXX}}{\field{\*\fldinst PAGEREF John XXXXX}}` +
+		`{\*\datastore 005858585800}{\object{\*\objdata 58005800` + "\r\n" + `58005800}}{\pict 4a6f686e}\par}`
+	var b bytes.Buffer
+	count, err := Redact(&b, strings.NewReader(in), RedactOptions{
+		Strings:  []string{"John", "Smith"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`john@[a-z.]+`)},
+		Mask:     'X',
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected || count != 12 {
+		t.Errorf("expected %q with 12 matches, got %q with %d", expected, b.String(), count)
+	}
+}
+
+func TestRedactSkipsProperties(t *testing.T) {
+	header := `{\rtf1\ansi\ansicpg1252{\fonttbl{\f0\froman\fcharset0\fprq2{\*\panose 02020603050405020304}Times New Roman;}}` +
+		`{\colortbl;\red0\green0\blue0;}{\stylesheet{\s0 Normal 1234567;}}{\*\listtable{\list\listtemplateid1033{\listlevel` +
+		`{\leveltext\leveltemplateid67698703\'02\'00.;}}\listid1234567}}{\*\passwordhash 0123456789abcdef}` +
+		`{\*\shppict{\pict{\*\blipuid 0123456789abcdef0123456789abcdef}\pngblip 89}}`
+	in := header + `\pard\f0 Times account 12345678\par}`
+	expected := header + `\pard\f0 XXXXX account XXXXXXXX\par}`
+	var b bytes.Buffer
+	count, err := Redact(&b, strings.NewReader(in), RedactOptions{
+		Strings:  []string{"Times"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{6,}`)},
+		Mask:     'X',
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected || count != 2 {
+		t.Errorf("expected %q with 2 matches, got %q with %d", expected, b.String(), count)
+	}
+}
+
+func TestRedactMask(t *testing.T) {
+	var b bytes.Buffer
+	count, err := Redact(&b, strings.NewReader(`{\rtf1 caf\'e9 {\uc2 caf\'e9}\par}`), RedactOptions{Strings: []string{"é"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mask := fmt.Sprintf(`\u%d?`, '█')
+	expected := `{\rtf1 caf` + mask + ` {\uc2 caf{\uc1 ` + mask + `}}\par}`
+	if b.String() != expected || count != 2 {
+		t.Errorf("expected %q with 2 matches, got %q with %d", expected, b.String(), count)
+	}
+
+	if _, err := Redact(&b, strings.NewReader(`{\rtf1 \bin9 ab}`), RedactOptions{Strings: []string{"a"}}); err == nil {
+		t.Error("expected error for truncated data")
+	}
+}
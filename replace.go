@@ -0,0 +1,102 @@
+package rtf2txt
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sort"
+)
+
+// textDestinations are the destinations whose text is part of the document
+var textDestinations = destinationsUsed(useText)
+
+// edit replaces a range of RTF data
+type edit struct {
+	start, end int64
+	text       string
+}
+
+// Replace is used to copy RTF from an io.Reader to an io.Writer, replacing
+// text in the document. The arguments are pairs of old and new text, which
+// are replaced in the order they appear in the document without overlapping
+// like strings.NewReplacer. Text is matched across control words and groups,
+// such as the \rsid runs Word splits words into, but not across paragraphs.
+// Only text is rewritten, so the replacement has the formatting of the
+// first character it replaces. The number of replacements is returned
+func Replace(w io.Writer, r io.Reader, oldnew ...string) (int, error) {
+	if len(oldnew)%2 == 1 {
+		return 0, errors.New("Replace requires pairs of old and new text")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	tokens, err := lex(data)
+	if err != nil {
+		return 0, err
+	}
+	chars := textChars(data, tokens, func(dest string) textMode {
+		if dest == "" || textDestinations[dest] {
+			return textRead
+		}
+		return textSkip
+	})
+
+	var edits []edit
+	count := 0
+	for i := 0; i < len(chars); {
+		matched := false
+		for p := 0; p < len(oldnew); p += 2 {
+			old := []rune(oldnew[p])
+			if len(old) == 0 || !matchChars(chars[i:], old) {
+				continue
+			}
+			edits = append(edits, replaceChars(chars[i:i+len(old)], oldnew[p+1])...)
+			i += len(old)
+			count++
+			matched = true
+			break
+		}
+		if !matched {
+			i++
+		}
+	}
+	return count, writeEdits(w, data, edits)
+}
+
+// matchChars returns whether chars start with text which can be rewritten
+func matchChars(chars []textChar, text []rune) bool {
+	if len(chars) < len(text) {
+		return false
+	}
+	for i, r := range text {
+		if chars[i].fixed || chars[i].r != r {
+			return false
+		}
+	}
+	return true
+}
+
+// replaceChars returns the edits which write text in place of the first
+// character and remove the rest
+func replaceChars(chars []textChar, text string) []edit {
+	edits := []edit{chars[0].rewrite(text)}
+	for _, c := range chars[1:] {
+		edits = append(edits, edit{c.start, c.end, ""})
+	}
+	return edits
+}
+
+// writeEdits writes the data with the edits applied
+func writeEdits(w io.Writer, data []byte, edits []edit) error {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	bw := bufio.NewWriter(w)
+	var pos int64
+	for _, e := range edits {
+		bw.Write(data[pos:e.start])
+		bw.WriteString(e.text)
+		pos = e.end
+	}
+	bw.Write(data[pos:])
+	return bw.Flush()
+}
@@ -0,0 +1,56 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		in, out string
+		oldnew  []string
+		count   int
+	}{
+		{`{\rtf1 Dear \{\{CLIENT\}\},\par}`, `{\rtf1 Dear ACME,\par}`, []string{"{{CLIENT}}", "ACME"}, 1},
+		{`{\rtf1 Dear {\b\insrsid1 \{\{CLI}{\insrsid2 ENT\}\}},\par}`, `{\rtf1 Dear {\b\insrsid1 ACME}{\insrsid2 },\par}`, []string{"{{CLIENT}}", "ACME"}, 1},
+		{`{\rtf1 {\*\generator \{\{CLIENT\}\};}\{\{CLIENT\}\}}`, `{\rtf1 {\*\generator \{\{CLIENT\}\};}X}`, []string{"{{CLIENT}}", "X"}, 1},
+		{`{\rtf1 a\par b}`, `{\rtf1 a\par b}`, []string{"ab", "X"}, 0},
+		{`{\rtf1 caf\'e9 and cafe}`, `{\rtf1 Caf\'e9 and Cafe}`, []string{"café", "Café", "cafe", "Cafe"}, 2},
+		{`{\rtf1 \u8364?5}`, `{\rtf1 EUR 5}`, []string{"€", "EUR "}, 1},
+		{`{\rtf1\b;x}`, `{\rtf1\b yx}`, []string{";", "y"}, 1},
+		{`{\rtf1 \{\{A\}\} \{\{B\}\}}`, `{\rtf1 \{\\a\} \'e9}`, []string{"{{A}}", `{\a}`, "{{B}}", "é"}, 2},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		count, err := Replace(&b, strings.NewReader(test.in), test.oldnew...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != test.out || count != test.count {
+			t.Errorf("expected %q with %d replacements, got %q with %d", test.out, test.count, b.String(), count)
+		}
+	}
+
+	if _, err := Replace(&bytes.Buffer{}, strings.NewReader(`{\rtf1}`), "a"); err == nil {
+		t.Error("expected error for odd arguments")
+	}
+	if _, err := Replace(&bytes.Buffer{}, strings.NewReader(`{\rtf1 \bin5 a}`), "a", "b"); err == nil {
+		t.Error("expected error for truncated binary data")
+	}
+}
+
+func TestReplaceKeepsText(t *testing.T) {
+	in := `{\rtf1\ansi{\fonttbl{\f0\fnil Arial;}}\pard\plain\f0\fs24 Hello {\b\f0\fs24 \{\{NA}{\rsid5\f0\fs24 ME\}\}}\f0\fs24 , welcome\par}`
+	var b bytes.Buffer
+	if _, err := Replace(&b, strings.NewReader(in), "{{NAME}}", "Jane"); err != nil {
+		t.Fatal(err)
+	}
+	text, err := Text(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "Hello Jane, welcome " {
+		t.Errorf("expected replaced text, got %q", text.String())
+	}
+}
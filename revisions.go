@@ -0,0 +1,96 @@
+package rtf2txt
+
+import (
+	"strings"
+)
+
+// RevisionView selects how tracked changes are shown in the converted text
+type RevisionView int
+
+const (
+	// RevisionsAccepted shows the document as if all changes were accepted.
+	// Deleted text is excluded and inserted text is included
+	RevisionsAccepted RevisionView = iota
+	// RevisionsRejected shows the original document as if all changes were
+	// rejected. Inserted text is excluded and deleted text is included
+	RevisionsRejected
+	// RevisionsMarked includes both inserted and deleted text. Insertions are
+	// written as "[+Author: text+]" and deletions as "[-Author: text-]"
+	RevisionsMarked
+)
+
+// revision is the kind of revision mark which is currently open in the
+// marked up view
+type revision struct {
+	deleted bool
+	author  string
+}
+
+// parseRevisionTable splits the text of a \revtbl destination into the
+// list of authors. Each author is terminated by a semicolon
+func parseRevisionTable(text string) []string {
+	authors := strings.Split(text, ";")
+	return authors[:len(authors)-1]
+}
+
+// author returns the name of the author with the index given by \revauthN
+// or \revauthdelN
+func (c *converter) author(index int) string {
+	if index < 0 || index >= len(c.authors) {
+		return "Unknown"
+	}
+	return c.authors[index]
+}
+
+// revisionVisible reports whether text with the revision properties of g is
+// part of the selected view
+func (c *converter) revisionVisible(g *group) bool {
+	switch c.opts.Revisions {
+	case RevisionsAccepted:
+		return !g.deleted
+	case RevisionsRejected:
+		return !g.inserted
+	default:
+		return true
+	}
+}
+
+// markRevision opens and closes the revision delimiters of the marked up
+// view as text moves in and out of revisions
+func (c *converter) markRevision(g *group) {
+	if c.opts.Revisions != RevisionsMarked {
+		return
+	}
+	var mark *revision
+	switch {
+	case g.deleted:
+		mark = &revision{true, c.author(g.delAuthor)}
+	case g.inserted:
+		mark = &revision{false, c.author(g.insAuthor)}
+	}
+	if mark != nil && c.mark != nil && *mark == *c.mark {
+		return
+	}
+	c.closeRevision()
+	if mark == nil {
+		return
+	}
+	if mark.deleted {
+		c.writeText("[-" + mark.author + ": ")
+	} else {
+		c.writeText("[+" + mark.author + ": ")
+	}
+	c.mark = mark
+}
+
+func (c *converter) closeRevision() {
+	if c.mark == nil {
+		return
+	}
+	if c.mark.deleted {
+		c.writeText("-]")
+	} else {
+		c.writeText("+]")
+	}
+	c.mark = nil
+}
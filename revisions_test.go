@@ -0,0 +1,56 @@
+package rtf2txt
+
+import (
+	"strings"
+	"testing"
+)
+
+const revisedDoc = `{\rtf1{\*\revtbl {Unknown;}{Jane Doe;}{J\'f6rg;}}\pard\f0 The term is ` +
+	`{\deleted\revauthdel1\revdttmdel1234\f0 30}{\revised\revauth2\revdttm1234\f0 60} days.` +
+	`{\revised\revauth1\f0  Renewal is automatic.}\par}`
+
+func TestRevisions(t *testing.T) {
+	r, err := Text(strings.NewReader(revisedDoc))
+	if err != nil || r.String() != "The term is 60 days. Renewal is automatic. " {
+		t.Error("expected accepted changes", err, r.String())
+	}
+
+	r, err = TextWithOptions(strings.NewReader(revisedDoc), Options{Revisions: RevisionsRejected})
+	if err != nil || r.String() != "The term is 30 days. " {
+		t.Error("expected rejected changes", err, r.String())
+	}
+
+	r, err = TextWithOptions(strings.NewReader(revisedDoc), Options{Revisions: RevisionsMarked})
+	expected := "The term is [-Jane Doe: 30-][+Jörg: 60+] days.[+Jane Doe:  Renewal is automatic.+] "
+	if err != nil || r.String() != expected {
+		t.Error("expected marked changes", err, r.String())
+	}
+}
+
+func TestRevisionToggles(t *testing.T) {
+	doc := `{\rtf1{\*\revtbl {Unknown;}{Jane Doe;}}\pard\f0 a \deleted\revauthdel1 b\deleted0  c ` +
+		`\revised\revauth1 d\revised0  e\par}`
+	tests := []struct {
+		view     RevisionView
+		expected string
+	}{
+		{RevisionsAccepted, "a  c d e "},
+		{RevisionsRejected, "a b c  e "},
+		{RevisionsMarked, "a [-Jane Doe: b-] c [+Jane Doe: d+] e "},
+	}
+	for _, test := range tests {
+		r, err := TextWithOptions(strings.NewReader(doc), Options{Revisions: test.view})
+		if err != nil || r.String() != test.expected {
+			t.Errorf("%v: expected %q, got %q %v", test.view, test.expected, r, err)
+		}
+	}
+}
+
+func TestParseRevisionTable(t *testing.T) {
+	if authors := parseRevisionTable("Unknown;Jane Doe;"); len(authors) != 2 || authors[1] != "Jane Doe" {
+		t.Error("expected authors", authors)
+	}
+	if authors := parseRevisionTable(""); len(authors) != 0 {
+		t.Error("expected no authors", authors)
+	}
+}
@@ -2,58 +2,374 @@ package rtf2txt
 
 import (
 	"bytes"
-	"errors"
+	"context"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/EndFirstCorp/peekingReader"
 )
 
+// Options control how RTF is converted to text
+type Options struct {
+	// IncludeHidden includes text formatted as hidden with \v. Hidden text is
+	// excluded by default since Word neither displays nor prints it. Text
+	// formatted with \webhidden is only hidden in web layout, so it is
+	// always included
+	IncludeHidden bool
+
+	// Revisions selects how text with tracked changes is shown. By default
+	// changes are accepted
+	Revisions RevisionView
+
+	// InlineComments writes each comment into the text after the text it is
+	// anchored to as "[Comment Author: text]"
+	InlineComments bool
+
+	// Limits bound the resources used by the conversion. A *LimitError is
+	// returned when any of them is exceeded
+	Limits Limits
+
+	// Lenient returns the text of truncated documents and skips malformed
+	// control words instead of failing. The problems are kept as warnings
+	// in the Document. Limits are still enforced
+	Lenient bool
+
+	// Diagnostics is called with anything the converter skips, such as
+	// control words it doesn't know
+	Diagnostics func(Diagnostic)
+
+	// ParagraphBreak is written at the end of each paragraph instead of a
+	// space, such as "\n"
+	ParagraphBreak string
+}
+
+// Document holds the text of an RTF document along with the other content
+// found while converting it
+type Document struct {
+	Text      string
+	Comments  []Comment
+	Bookmarks []Bookmark
+	Images    []Image
+	Objects   []Object
+
+	// Warnings are the problems skipped in lenient mode
+	Warnings []*ParseError
+}
+
 // Text is used to convert an io.Reader containing RTF data into
 // plain text
 func Text(r io.Reader) (*bytes.Buffer, error) {
-	pr := peekingReader.NewBufReader(r)
+	return TextWithOptions(r, Options{})
+}
+
+// Convert is used to convert an io.Reader containing RTF data into a
+// Document using the supplied options
+func Convert(r io.Reader, opts Options) (*Document, error) {
+	return ConvertContext(context.Background(), r, opts)
+}
+
+// ConvertContext is like Convert but stops when ctx is done. In that case
+// the Document converted so far is returned along with ctx.Err()
+func ConvertContext(ctx context.Context, r io.Reader, opts Options) (*Document, error) {
+	c := newConverter(peekingReader.NewBufReader(limitInput(r, opts.Limits)), opts)
+	c.setContext(ctx)
+	if err := c.convert(); err != nil {
+		if err == ctx.Err() {
+			return c.document(), err
+		}
+		return nil, err
+	}
+	return c.document(), nil
+}
+
+func (c *converter) document() *Document {
+	return &Document{Text: c.text.String(), Comments: c.comments, Bookmarks: c.bookmarks,
+		Images: c.images, Objects: c.objects, Warnings: c.warnings}
+}
+
+// TextWithOptions is used to convert an io.Reader containing RTF data into
+// plain text using the supplied options
+func TextWithOptions(r io.Reader, opts Options) (*bytes.Buffer, error) {
+	return TextContext(context.Background(), r, opts)
+}
+
+// TextContext is like TextWithOptions but stops when ctx is done. In that
+// case the text converted so far is returned along with ctx.Err(). The
+// context is checked as the data is read, so a read which blocks is not
+// interrupted
+func TextContext(ctx context.Context, r io.Reader, opts Options) (*bytes.Buffer, error) {
+	c := newConverter(peekingReader.NewBufReader(limitInput(r, opts.Limits)), opts)
+	c.setContext(ctx)
+	if err := c.convert(); err != nil {
+		if err == ctx.Err() {
+			return &c.text, err
+		}
+		return nil, err
+	}
+	return &c.text, nil
+}
 
-	var text bytes.Buffer
-	var symbolStack stack
-	for b, err := pr.ReadByte(); err == nil; b, err = pr.ReadByte() {
+// converter holds the state needed while converting a single RTF document
+type converter struct {
+	ctx      context.Context
+	r        peekingReader.Reader
+	opts     Options
+	symbols  stack
+	text     bytes.Buffer
+	runes    int // number of characters in text
+	captured int // bytes of text and data captured from destinations
+	groups   []group
+	lists    listTable
+	para     paragraph
+	authors  []string  // \revtbl
+	codePage int       // code page of \'hh escapes
+	mark     *revision // revision open in the marked up view
+
+	comments []Comment
+	comment  Comment           // comment being read
+	anchors  map[string][2]int // \atrfstart and \atrfend offsets, by ID
+
+	bookmarks []Bookmark
+	images    []Image
+	picture   *Image // picture being read
+	objects   []Object
+
+	start    int64  // offset of the control word being handled
+	control  string // control word being handled
+	controls int    // number of control words read
+	err      error  // error found while writing, returned by convert
+	warnings []*ParseError
+	maxDepth int       // deepest nesting of groups
+	analysis *Analysis // set when analyzing the document
+}
+
+// group holds the properties that RTF saves on '{' and restores on '}'
+type group struct {
+	dest destination
+	out  *bytes.Buffer     // when set, text is captured here instead of the document text
+	raw  bool              // capture all text, not only text following formatting controls
+	done func(text string) // called with the captured text when the destination closes
+	ls   int               // \lsN list override of the current paragraph
+	ilvl int               // \ilvlN list level of the current paragraph
+
+	hidden    bool // \v hidden text
+	inserted  bool // \revised
+	insAuthor int  // \revauthN
+	deleted   bool // \deleted
+	delAuthor int  // \revauthdelN
+}
+
+// destination identifies the RTF destination a group belongs to
+type destination int
+
+const (
+	destText destination = iota
+	destListTable
+	destList
+	destListLevel
+	destListOverrideTable
+	destListOverride
+	destLfoLevel
+	destRevisionTable
+	destComment
+	destBookmark
+	destPicture
+	destObject
+)
+
+// paragraph holds the state of the paragraph currently being written
+type paragraph struct {
+	started bool   // text has been written for this paragraph
+	cached  string // text of a \listtext or \pntext group preceding the paragraph
+}
+
+func newConverter(r peekingReader.Reader, opts Options) *converter {
+	return &converter{ctx: context.Background(), r: newPositionReader(r), opts: opts, groups: []group{{}},
+		codePage: defaultCodePage, anchors: make(map[string][2]int)}
+}
+
+// setContext sets the context which stops the conversion. It is checked by
+// the reader, so that reading a large group or \binN data stops too
+func (c *converter) setContext(ctx context.Context) {
+	c.ctx = ctx
+	if p, ok := c.r.(*positionReader); ok {
+		p.ctx = ctx
+	}
+}
+
+func (c *converter) convert() error {
+	for {
+		b, err := c.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return c.stop(c.parseError(err))
+		}
 		switch b {
 		case '\\':
-			err := readControl(pr, &symbolStack, &text)
-			if err != nil {
-				return nil, err
-			}
-		case '{', '}':
+			err = c.readControl()
+		case '{':
+			c.pushGroup()
+			err = c.opts.Limits.checkDepth(len(c.groups) - 1)
+		case '}':
+			c.popGroup()
 		case '\n', '\r': // noop
 		default:
-			text.WriteByte(b)
+			c.write(string(b))
+		}
+		if err != nil {
+			if stop, err := c.tolerate(err); stop {
+				return c.stop(err)
+			}
 		}
+		if c.err != nil {
+			return c.parseError(c.err)
+		}
+	}
+	if open := len(c.groups) - 1; open > 0 {
+		c.diagnose(DiagnosticUnbalancedBraces, c.offset(), "", fmt.Sprintf("%d groups are not closed", open))
+	}
+	c.finish()
+	return nil
+}
+
+// stop returns the error which stopped the conversion. When the context is
+// done, the content converted so far is completed
+func (c *converter) stop(err error) error {
+	if err == c.ctx.Err() {
+		c.finish()
+	}
+	return err
+}
+
+// finish completes the text and the content which refers to it
+func (c *converter) finish() {
+	c.endParagraph()
+	c.closeRevision()
+	text := []rune(c.text.String())
+	c.anchorComments(text)
+	c.anchorBookmarks(text)
+	c.anchorObjects(text)
+}
+
+func (c *converter) group() *group {
+	return &c.groups[len(c.groups)-1]
+}
+
+func (c *converter) pushGroup() {
+	g := *c.group()
+	g.done = nil // only the group which started a destination finishes it
+	c.groups = append(c.groups, g)
+	if len(c.groups)-1 > c.maxDepth {
+		c.maxDepth = len(c.groups) - 1
+	}
+}
+
+func (c *converter) popGroup() {
+	if len(c.groups) == 1 { // unbalanced closing brace
+		c.diagnose(DiagnosticUnbalancedBraces, c.offset()-1, "", "closing brace without an open group")
+		return
+	}
+	g := c.group()
+	c.groups = c.groups[:len(c.groups)-1]
+	if g.done != nil {
+		var text string
+		if g.out != nil {
+			text = g.out.String()
+		}
+		g.done(text)
+	}
+}
+
+// capture sends the text of the current group to a new buffer, calling done
+// with the result when the group closes
+func (c *converter) capture(dest destination, raw bool, done func(text string)) {
+	g := c.group()
+	g.dest = dest
+	g.out = &bytes.Buffer{}
+	g.raw = raw
+	g.done = done
+}
+
+func (c *converter) write(s string) {
+	g := c.group()
+	if !g.raw && (g.hidden && !c.opts.IncludeHidden || !c.revisionVisible(g)) {
+		return
+	}
+	if g.out != nil {
+		if c.checkOutput(len(s)) {
+			g.out.WriteString(s)
+			c.captured += len(s)
+		}
+		return
+	}
+	if !c.para.started {
+		c.para.started = true
+		c.writeText(c.listNumber())
+	}
+	if s != "" {
+		c.markRevision(g)
+	}
+	c.writeText(s)
+}
+
+// writeText writes directly to the document text
+func (c *converter) writeText(s string) {
+	if !c.checkOutput(len(s)) {
+		return
+	}
+	c.text.WriteString(s)
+	c.runes += utf8.RuneCountInString(s)
+}
+
+// listNumber returns the number or bullet that Word displays at the start
+// of the current paragraph
+func (c *converter) listNumber() string {
+	g := c.group()
+	if number, found := c.lists.number(g.ls, g.ilvl); found {
+		return number
 	}
-	return &text, nil
+	return c.para.cached
 }
 
-func readControl(r peekingReader.Reader, s *stack, text *bytes.Buffer) error {
+func (c *converter) endParagraph() {
+	if c.group().out != nil { // paragraphs in other destinations don't affect the document
+		return
+	}
+	if c.para.started || c.group().ls == 0 {
+		c.para = paragraph{}
+		return
+	}
+	c.write("") // empty list paragraphs still show their number
+	c.para = paragraph{}
+}
+
+func (c *converter) readControl() error {
+	r := c.r
+	start := c.offset() - 1 // include the backslash
 	control, num, err := tokenizeControl(r)
 	if err != nil {
 		return err
 	}
+	c.analyzeControl(start, control, num)
+	c.start, c.control = start, control
+	if err := c.checkControls(); err != nil {
+		return err
+	}
 	if control == "*" { // this is an extended control sequence
-		err := readUntilClosingBrace(r)
-		if err != nil {
+		control, num, err = c.readExtended()
+		if err != nil || control == "" {
 			return err
 		}
-		if last := s.Peek(); last != "" {
-			val, err := getParams(r) // last control was interrupted, so finish handling Params
-			handleParams(control, val, text)
-			return err
-		}
-		return nil
 	}
+	c.diagnoseControl(control, num)
 	if isUnicode, u := getUnicode(control); isUnicode {
-		text.WriteString(u)
+		c.write(decodeCodePage(u, c.codePage))
 		return nil
 	}
 	if control == "" {
@@ -62,35 +378,184 @@ func readControl(r peekingReader.Reader, s *stack, text *bytes.Buffer) error {
 			return err
 		}
 		if p[0] == '\\' || p[0] == '{' || p[0] == '}' { // this is an escaped character
-			text.WriteByte(p[0])
+			c.write(string(p[0]))
 			r.ReadByte()
 			return nil
 		}
-		text.WriteByte('\n')
+		c.write("\n")
 		return nil
 	}
 	if control == "binN" {
-		return handleBinary(r, control, num)
+		if err := c.opts.Limits.checkBinary(num); err != nil {
+			return err
+		}
+		data, err := handleBinary(r, control, num)
+		if err != nil {
+			c.finding(start, fmt.Sprintf("\\bin length %d is invalid or exceeds the remaining data", num))
+			return err
+		}
+		if c.group().dest == destPicture && c.checkOutput(len(data)) {
+			c.picture.Data = append(c.picture.Data, data...)
+			c.captured += len(data)
+		}
+		return nil
+	}
+
+	if control == "nonshppict" { // the same picture as \shppict in an older format
+		c.diagnose(DiagnosticIgnoredPicture, start, control, "\\nonshppict is skipped in favor of \\shppict")
+		return c.skipGroup()
 	}
 
+	c.handleControl(control, num)
 	if symbol, found := convertSymbol(control); found {
-		text.WriteString(symbol)
+		if control == "par" && c.opts.ParagraphBreak != "" {
+			symbol = c.opts.ParagraphBreak
+		}
+		c.write(symbol)
+	}
+	if control == "par" {
+		c.endParagraph()
 	}
 
-	val, err := getParams(r)
+	val, err := getParams(r) // text before the end of truncated data is still handled
+	if c.group().raw {
+		c.write(c.rawParams(control, num, val))
+	} else {
+		c.handleParams(control, val)
+	}
+	c.symbols.Push(control)
+	return err
+}
+
+// extendedDestinations are the destinations after \* which are understood
+var extendedDestinations = destinationsUsed(useExtended)
+
+// readExtended handles a \* control. Destinations that are understood are
+// returned so that they can be handled like any other control. The rest are
+// skipped
+func (c *converter) readExtended() (string, int, error) {
+	r := c.r
+	if p, err := r.Peek(1); err == nil && p[0] == '\\' {
+		r.ReadByte()
+		start := c.offset() - 1
+		control, num, err := tokenizeControl(r)
+		if err != nil {
+			return "", -1, err
+		}
+		c.analyzeControl(start, control, num)
+		c.start, c.control = start, control
+		if extendedDestinations[control] {
+			return control, num, nil
+		}
+		c.diagnose(DiagnosticUnsupportedDestination, start, control, fmt.Sprintf("\\*\\%s is skipped", control))
+	}
+
+	if err := c.skipGroup(); err != nil {
+		return "", -1, err
+	}
+	if last := c.symbols.Peek(); last != "" {
+		val, err := getParams(r) // last control was interrupted, so finish handling Params
+		c.handleParams("*", val)
+		return "", -1, err
+	}
+	return "", -1, nil
+}
+
+// skipGroup skips the rest of the current group, including its closing brace
+func (c *converter) skipGroup() error {
+	err := readUntilClosingBrace(c.r)
 	if err != nil {
 		return err
 	}
-	handleParams(control, val, text)
-	s.Push(control)
+	c.popGroup()
 	return nil
 }
 
+// handleControl updates the converter state for destinations and the
+// paragraph properties that affect the text output
+func (c *converter) handleControl(control string, num int) {
+	g := c.group()
+	switch control {
+	case "pard":
+		g.ls, g.ilvl = 0, 0
+	case "lsN":
+		if g.dest == destListOverride {
+			c.lists.override.ls = num
+		} else {
+			g.ls = num
+		}
+	case "ilvlN":
+		g.ilvl = num
+	case "plain":
+		g.hidden = false
+		g.inserted, g.insAuthor = false, 0
+		g.deleted, g.delAuthor = false, 0
+	case "revised":
+		g.inserted = true
+	case "revisedN":
+		g.inserted = num != 0
+	case "revauthN":
+		g.insAuthor = num
+	case "deleted":
+		g.deleted = true
+	case "deletedN":
+		g.deleted = num != 0
+	case "revauthdelN":
+		g.delAuthor = num
+	case "ansi", "mac", "pc", "pca":
+		c.codePage = characterSets[control]
+	case "ansicpgN":
+		c.codePage = num
+	case "revtbl":
+		c.capture(destRevisionTable, true, func(text string) { c.authors = parseRevisionTable(text) })
+	case "annotation", "atndate", "atnauthor", "atnid", "atnref", "atrfend", "atrfstart":
+		c.handleComment(control)
+	case "bkmkstart", "bkmkend":
+		c.handleBookmark(control)
+	case "object", "objclass", "objdata", "objupdate", "objautlink", "result":
+		c.handleObject(control)
+	case "v":
+		g.hidden = true
+	case "vN":
+		g.hidden = num != 0
+	case "listtext", "pntext":
+		c.capture(destText, true, func(text string) { c.para.cached = text })
+	case "listtable":
+		c.capture(destListTable, false, nil)
+	case "listoverridetable":
+		c.capture(destListOverrideTable, false, nil)
+	default:
+		c.lists.handleControl(c, control, num)
+		c.handlePicture(control, num)
+	}
+}
+
+// rawParams returns the parameter text of a control inside a raw capture
+// group, resolving \uN to its character and dropping its fallback
+func (c *converter) rawParams(control string, num int, param string) string {
+	if strings.HasPrefix(param, " ") {
+		param = param[1:]
+	}
+	if control != "uN" {
+		return param
+	}
+	if num < 0 {
+		num += 65536
+	}
+	if param != "" {
+		param = param[1:] // fallback character
+	}
+	return string(rune(num)) + param
+}
+
 func tokenizeControl(r peekingReader.Reader) (string, int, error) {
 	var buf bytes.Buffer
 	isHex := false
 	numStart := -1
 	for {
+		if isHex && buf.Len() == 3 { // hex is always two digits
+			return buf.String(), -1, nil
+		}
 		p, err := r.Peek(1)
 		if err != nil {
 			return "", -1, err
@@ -104,11 +569,16 @@ func tokenizeControl(r peekingReader.Reader) (string, int, error) {
 			isHex = true
 			buf.WriteByte(b)
 			r.ReadByte()
+		case isHex && isHexDigit(b):
+			buf.WriteByte(b)
+			r.ReadByte()
+		case isHex:
+			return buf.String(), -1, nil
 		case b >= '0' && b <= '9' || b == '-':
 			if numStart == -1 {
 				numStart = buf.Len()
 			} else if numStart == 0 {
-				return "", -1, errors.New("Unexpected control sequence. Cannot begin with digit")
+				return "", -1, fmt.Errorf("%w. Cannot begin with digit", ErrMalformedControl)
 			}
 			buf.WriteByte(b)
 			r.ReadByte() // consume valid digit
@@ -120,9 +590,6 @@ func tokenizeControl(r peekingReader.Reader) (string, int, error) {
 			buf.WriteByte(b)
 			r.ReadByte()
 		default:
-			if isHex {
-				return buf.String(), -1, nil
-			}
 			c, num := canonicalize(buf.String(), numStart)
 			return c, num, nil
 		}
@@ -162,7 +629,7 @@ func getUnicode(control string) (bool, string) {
 func getParams(r peekingReader.Reader) (string, error) {
 	data, err := peekingReader.ReadUntilAny(r, []byte{'\\', '{', '}', '\n', '\r', ';'})
 	if err != nil {
-		return "", err
+		return string(data), err
 	}
 	p, err := r.Peek(1)
 	if err != nil {
@@ -175,16 +642,22 @@ func getParams(r peekingReader.Reader) (string, error) {
 	return string(data), nil
 }
 
-func handleBinary(r peekingReader.Reader, control string, size int) error {
+func handleBinary(r peekingReader.Reader, control string, size int) ([]byte, error) {
 	if control != "binN" { // wrong control type
-		return nil
+		return nil, nil
 	}
 
-	_, err := r.ReadBytes(size)
-	if err != nil {
-		return err
+	if size < 0 {
+		return nil, fmt.Errorf("%w. Invalid binary data length", ErrMalformedControl)
 	}
-	return nil
+	if p, err := r.Peek(1); err == nil && p[0] == ' ' { // delimiter is not part of the data
+		r.ReadByte()
+	}
+	var data bytes.Buffer // grows with the data actually read rather than the size claimed
+	if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
 }
 
 func readUntilClosingBrace(r peekingReader.Reader) error {
@@ -205,13 +678,18 @@ func readUntilClosingBrace(r peekingReader.Reader) error {
 	return err
 }
 
-func handleParams(control, param string, text *bytes.Buffer) {
+func (c *converter) handleParams(control, param string) {
 	if strings.HasPrefix(param, " ") {
 		param = param[1:]
 	}
-	if param == "" {
-		return
+	if param != "" && showsParams(control) {
+		c.write(param)
 	}
+}
+
+// showsParams returns whether the text after a control word is written to
+// the document. The text after other control words is dropped
+func showsParams(control string) bool {
 	switch control {
 	// Absolution Position Tabs
 	// case "pindtabqc", "pindtabql", "pindtabqr", "pmartabqc", "pmartabql", "pmartabqr", "ptabldot", "ptablmdot", "ptablminus", "ptablnone", "ptabluscore":
@@ -229,7 +707,9 @@ func handleParams(control, param string, text *bytes.Buffer) {
 	// case "chbgbdiag","chbgcross","chbgdcross","chbgdkbdiag","chbgdkcross","chbgdkdcross","chbgdkfdiag","chbgdkhoriz","chbgdkvert","chbgfdiag","chbghoriz","chbgvert","chbrdr","chcbpatN","chcfpatN","chshdngN":
 
 	// Character Revision Mark Properties
-	// case "crauthN","crdateN","deleted","mvauthN ","mvdateN ","mvf","mvt","revauthdelN","revauthN ","revdttmdelN","revdttmN ","revised":
+	// case "crauthN","crdateN","mvauthN ","mvdateN ","mvf","mvt":
+	case "deleted", "deletedN", "revauthdelN", "revauthN", "revdttmdelN", "revdttmN", "revised", "revisedN":
+		return true
 
 	// Character Set
 	// case "ansi","ansicpgN","fbidis","mac","pc","pca","impr","striked1":
@@ -279,14 +759,14 @@ func handleParams(control, param string, text *bytes.Buffer) {
 	// Fields
 	// case "datafield ","date","field","fldalt ","flddirty","fldedit","fldinst","fldlock","fldpriv","fldrslt","fldtype","time","wpeqn":
 	case "fldrslt":
-		text.WriteString(param)
+		return true
 
 	// File Table
 	// case "fidN ","file ","filetbl ","fnetwork ","fnonfilesys","fosnumN ","frelativeN ","fvaliddos ","fvalidhpfs ","fvalidmac ","fvalidntfs ":
 
 	// Font (Character) Formatting Properties
-	case "acccircle", "acccomma", "accdot", "accnone", "accunderdot", "animtextN", "b", "caps", "cbN", "cchsN ", "cfN", "charscalexN", "csN", "dnN", "embo", "expndN", "expndtwN ", "fittextN", "fN", "fsN", "i", "kerningN ", "langfeN", "langfenpN", "langN", "langnpN", "ltrch", "noproof", "nosupersub ", "outl", "plain", "rtlch", "scaps", "shad", "strike", "sub ", "super ", "ul", "ulcN", "uld", "uldash", "uldashd", "uldashdd", "uldb", "ulhwave", "ulldash", "ulnone", "ulth", "ulthd", "ulthdash", "ulthdashd", "ulthdashdd", "ulthldash", "ululdbwave", "ulw", "ulwave", "upN", "v", "webhidden":
-		text.WriteString(param)
+	case "acccircle", "acccomma", "accdot", "accnone", "accunderdot", "animtextN", "b", "caps", "cbN", "cchsN ", "cfN", "charscalexN", "csN", "dnN", "embo", "expndN", "expndtwN ", "fittextN", "fN", "fsN", "i", "kerningN ", "langfeN", "langfenpN", "langN", "langnpN", "ltrch", "noproof", "nosupersub ", "outl", "plain", "rtlch", "scaps", "shad", "strike", "sub ", "super ", "ul", "ulcN", "uld", "uldash", "uldashd", "uldashdd", "uldb", "ulhwave", "ulldash", "ulnone", "ulth", "ulthd", "ulthdash", "ulthdashd", "ulthdashdd", "ulthldash", "ululdbwave", "ulw", "ulwave", "upN", "v", "vN", "webhidden":
+		return true
 
 	// Font Family
 	// case "fjgothic","fjminchou","jis","falt ","fbiasN","fbidi","fcharsetN","fdecor","fetch","fmodern","fname","fnil","fontemb","fontfile","fonttbl","fprqN ","froman","fscript","fswiss","ftech","ftnil","fttruetype","panose":
@@ -347,7 +827,7 @@ func handleParams(control, param string, text *bytes.Buffer) {
 
 	// Paragraph Formatting Properties
 	case "aspalpha", "aspnum", "collapsed", "contextualspace", "cufiN", "culiN", "curiN", "faauto", "facenter", "fafixed", "fahang", "faroman", "favar", "fiN", "hyphpar ", "indmirror", "intbl", "itapN", "keep", "keepn", "levelN", "liN", "linN", "lisaN", "lisbN", "ltrpar", "nocwrap", "noline", "nooverflow", "nosnaplinegrid", "nowidctlpar ", "nowwrap", "outlinelevelN ", "pagebb", "pard", "prauthN", "prdateN", "qc", "qd", "qj", "qkN", "ql", "qr", "qt", "riN", "rinN", "rtlpar", "saautoN", "saN", "sbautoN", "sbN", "sbys", "slmultN", "slN", "sN", "spv", "subdocumentN ", "tscbandhorzeven", "tscbandhorzodd", "tscbandverteven", "tscbandvertodd", "tscfirstcol", "tscfirstrow", "tsclastcol", "tsclastrow", "tscnecell", "tscnwcell", "tscsecell", "tscswcell", "txbxtwalways", "txbxtwfirst", "txbxtwfirstlast", "txbxtwlast", "txbxtwno", "widctlpar", "ytsN":
-		text.WriteString(param)
+		return true
 
 	// Paragraph Group Properties
 	// case "pgp","pgptbl","ipgpN":
@@ -381,18 +861,18 @@ func handleParams(control, param string, text *bytes.Buffer) {
 
 	// Section Formatting Properties
 	case "adjustright", "binfsxnN", "binsxnN", "colnoN ", "colsN", "colsrN ", "colsxN", "colwN ", "dsN", "endnhere", "footeryN", "guttersxnN", "headeryN", "horzsect", "linebetcol", "linecont", "linemodN", "lineppage", "linerestart", "linestartsN", "linexN", "lndscpsxn", "ltrsect", "margbsxnN", "marglsxnN", "margmirsxn", "margrsxnN", "margtsxnN", "pghsxnN", "pgnbidia", "pgnbidib", "pgnchosung", "pgncnum", "pgncont", "pgndbnum", "pgndbnumd", "pgndbnumk", "pgndbnumt", "pgndec", "pgndecd", "pgnganada", "pgngbnum", "pgngbnumd", "pgngbnumk", "pgngbnuml", "pgnhindia", "pgnhindib", "pgnhindic", "pgnhindid", "pgnhnN ", "pgnhnsc ", "pgnhnsh ", "pgnhnsm ", "pgnhnsn ", "pgnhnsp ", "pgnid", "pgnlcltr", "pgnlcrm", "pgnrestart", "pgnstartsN", "pgnthaia", "pgnthaib", "pgnthaic", "pgnucltr", "pgnucrm", "pgnvieta", "pgnxN", "pgnyN", "pgnzodiac", "pgnzodiacd", "pgnzodiacl", "pgwsxnN", "pnseclvlN", "rtlsect", "saftnnalc", "saftnnar", "saftnnauc", "saftnnchi", "saftnnchosung", "saftnncnum", "saftnndbar", "saftnndbnum", "saftnndbnumd", "saftnndbnumk", "saftnndbnumt", "saftnnganada", "saftnngbnum", "saftnngbnumd", "saftnngbnumk", "saftnngbnuml", "saftnnrlc", "saftnnruc", "saftnnzodiac", "saftnnzodiacd", "saftnnzodiacl", "saftnrestart", "saftnrstcont", "saftnstartN", "sbkcol", "sbkeven", "sbknone", "sbkodd", "sbkpage", "sectd", "sectdefaultcl", "sectexpandN", "sectlinegridN", "sectspecifycl", "sectspecifygenN", "sectspecifyl", "sectunlocked", "sftnbj", "sftnnalc", "sftnnar", "sftnnauc", "sftnnchi", "sftnnchosung", "sftnncnum", "sftnndbar", "sftnndbnum", "sftnndbnumd", "sftnndbnumk", "sftnndbnumt", "sftnnganada", "sftnngbnum", "sftnngbnumd", "sftnngbnumk", "sftnngbnuml", "sftnnrlc", "sftnnruc", "sftnnzodiac", "sftnnzodiacd", "sftnnzodiacl", "sftnrestart", "sftnrstcont", "sftnrstpg", "sftnstartN", "sftntj", "srauthN", "srdateN", "titlepg", "vertal", "vertalb", "vertalc", "vertalj", "vertalt", "vertsect":
-		text.WriteString(param)
+		return true
 
 	// Section Text
 	case "stextflowN":
-		text.WriteString(param)
+		return true
 
 	// SmartTag Data
 	// case "factoidname":
 
 	// Special Characters
 	case "-", ":", "_", "{", "|", "}", "~", "bullet", "chatn", "chdate", "chdpa", "chdpl", "chftn", "chftnsep", "chftnsepc", "chpgn", "chtime", "column", "emdash", "emspace ", "endash", "enspace ", "lbrN", "ldblquote", "line", "lquote", "ltrmark", "page", "par", "qmspace", "rdblquote", "row", "rquote", "rtlmark", "sect", "sectnum", "softcol ", "softlheightN ", "softline ", "softpage ", "tab", "zwbo", "zwj", "zwnbo", "zwnj":
-		text.WriteString(param)
+		return true
 
 	// Style and Formatting Restrictions
 	// case "latentstyles","lsdlockeddefN","lsdlockedexcept","lsdlockedN","lsdprioritydefN","lsdpriorityN","lsdqformatdefN","lsdqformatN","lsdsemihiddendefN","lsdsemihiddenN","lsdstimaxN","lsdunhideuseddefN","lsdunhideusedN":
@@ -402,18 +882,18 @@ func handleParams(control, param string, text *bytes.Buffer) {
 
 	// Table Definitions
 	case "cell", "cellxN", "clbgbdiag", "clbgcross", "clbgdcross", "clbgdkbdiag", "clbgdkcross", "clbgdkdcross", "clbgdkfdiag", "clbgdkhor", "clbgdkvert", "clbgfdiag", "clbghoriz", "clbgvert", "clbrdrb", "clbrdrl", "clbrdrr", "clbrdrt", "clcbpatN", "clcbpatrawN", "clcfpatN", "clcfpatrawN", "cldel2007", "cldelauthN", "cldeldttmN", "cldgll", "cldglu", "clFitText", "clftsWidthN", "clhidemark", "clins", "clinsauthN", "clinsdttmN", "clmgf", "clmrg", "clmrgd", "clmrgdauthN", "clmrgddttmN", "clmrgdr", "clNoWrap", "clpadbN", "clpadfbN", "clpadflN", "clpadfrN", "clpadftN", "clpadlN", "clpadrN", "clpadtN", "clshdngN", "clshdngrawN", "clshdrawnil", "clspbN", "clspfbN", "clspflN", "clspfrN", "clspftN", "clsplit", "clsplitr", "clsplN", "clsprN", "clsptN", "cltxbtlr", "cltxlrtb", "cltxlrtbv", "cltxtbrl", "cltxtbrlv", "clvertalb", "clvertalc", "clvertalt", "clvmgf", "clvmrg", "clwWidthN", "irowbandN", "irowN", "lastrow", "ltrrow", "nestcell", "nestrow", "nesttableprops", "nonesttables", "rawclbgbdiag", "rawclbgcross", "rawclbgdcross", "rawclbgdkbdiag", "rawclbgdkcross", "rawclbgdkdcross", "rawclbgdkfdiag", "rawclbgdkhor", "rawclbgdkvert", "rawclbgfdiag", "rawclbghoriz", "rawclbgvert", "rtlrow", "tabsnoovrlp", "taprtl", "tblindN", "tblindtypeN", "tbllkbestfit", "tbllkborder", "tbllkcolor", "tbllkfont", "tbllkhdrcols", "tbllkhdrrows", "tbllklastcol", "tbllklastrow", "tbllknocolband", "tbllknorowband", "tbllkshading", "tcelld", "tdfrmtxtBottomN", "tdfrmtxtLeftN", "tdfrmtxtRightN", "tdfrmtxtTopN", "tphcol", "tphmrg", "tphpg", "tposnegxN", "tposnegyN", "tposxc", "tposxi", "tposxl", "tposxN", "tposxo", "tposxr", "tposyb", "tposyc", "tposyil", "tposyin", "tposyN", "tposyout", "tposyt", "tpvmrg", "tpvpara", "tpvpg", "trauthN", "trautofitN", "trbgbdiag", "trbgcross", "trbgdcross", "trbgdkbdiag", "trbgdkcross", "trbgdkdcross", "trbgdkfdiag", "trbgdkhor", "trbgdkvert", "trbgfdiag", "trbghoriz", "trbgvert", "trbrdrb ", "trbrdrh ", "trbrdrl ", "trbrdrr ", "trbrdrt ", "trbrdrv ", "trcbpatN", "trcfpatN", "trdateN", "trftsWidthAN", "trftsWidthBN", "trftsWidthN", "trgaphN", "trhdr ", "trkeep ", "trkeepfollow", "trleftN", "trowd", "trpaddbN", "trpaddfbN", "trpaddflN", "trpaddfrN", "trpaddftN", "trpaddlN", "trpaddrN", "trpaddtN", "trpadobN", "trpadofbN", "trpadoflN", "trpadofrN", "trpadoftN", "trpadolN", "trpadorN", "trpadotN", "trpatN", "trqc", "trql", "trqr", "trrhN", "trshdngN", "trspdbN", "trspdfbN", "trspdflN", "trspdfrN", "trspdftN", "trspdlN", "trspdrN", "trspdtN", "trspobN", "trspofbN", "trspoflN", "trspofrN", "trspoftN", "trspolN", "trsporN", "trspotN", "trwWidthAN", "trwWidthBN", "trwWidthN":
-		text.WriteString(param)
+		return true
 
 	// Table of Contents Entries
 	case "tc", "tcfN", "tclN", "tcn ":
-		text.WriteString(param)
+		return true
 
 	// Table Styles
 	// case "tsbgbdiag","tsbgcross","tsbgdcross","tsbgdkbdiag","tsbgdkcross","tsbgdkdcross","tsbgdkfdiag","tsbgdkhor","tsbgdkvert","tsbgfdiag","tsbghoriz","tsbgvert","tsbrdrb","tsbrdrdgl","tsbrdrdgr","tsbrdrh","tsbrdrl","tsbrdrr","tsbrdrr","tsbrdrt","tsbrdrv","tscbandshN","tscbandsvN","tscellcbpatN","tscellcfpatN","tscellpaddbN","tscellpaddfbN","tscellpaddflN","tscellpaddfrN","tscellpaddftN","tscellpaddlN","tscellpaddrN","tscellpaddtN","tscellpctN","tscellwidthftsN","tscellwidthN","tsnowrap","tsvertalb","tsvertalc","tsvertalt":
 
 	// Tabs
 	case "tbN", "tldot", "tleq", "tlhyph", "tlmdot", "tlth", "tlul", "tqc", "tqdec", "tqr", "txN":
-		text.WriteString(param)
+		return true
 
 	// Theme Data
 	// case "themedata":
@@ -436,6 +916,7 @@ func handleParams(control, param string, text *bytes.Buffer) {
 	// Word through Word RTF for Drawing Objects (Shapes)
 	// case "shp","shpbottomN","shpbxcolumn","shpbxignore","shpbxmargin","shpbxpage","shpbyignore","shpbymargin","shpbypage","shpbypara","shpfblwtxtN","shpfhdrN","shpgrp","shpinst","shpleftN","shplidN","shplockanchor","shprightN","shprslt","shptopN","shptxt","shpwrkN","shpwrN","shpzN","sn","sp","sv","svb":
 	default:
+		return false
 	}
 }
 
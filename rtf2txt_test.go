@@ -1,10 +1,11 @@
 package rtf2txt
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/EndFirstCorp/peekingReader"
@@ -42,63 +43,134 @@ func Test2Text(t *testing.T) {
 	}
 }
 
+func TestParagraphBreak(t *testing.T) {
+	doc := `{\rtf1\pard\f0 one\par two\line three\par}`
+	r, err := TextWithOptions(strings.NewReader(doc), Options{ParagraphBreak: "\n\n"})
+	if err != nil || r.String() != "one\n\ntwo\nthree\n\n" {
+		t.Errorf("expected paragraph breaks %q %v", r.String(), err)
+	}
+}
+
+func TestHiddenText(t *testing.T) {
+	const doc = `{\rtf1\pard\f0 Public {\v secret }text {\v\f0 hidden \v0 shown \v again \plain plain}\par}`
+	r, err := Text(strings.NewReader(doc))
+	if err != nil || r.String() != "Public text shown plain " {
+		t.Error("expected hidden text to be excluded", err, r.String())
+	}
+
+	r, err = TextWithOptions(strings.NewReader(doc), Options{IncludeHidden: true})
+	if err != nil || r.String() != "Public secret text hidden shown again plain " {
+		t.Error("expected hidden text to be included", err, r.String())
+	}
+
+	r, err = Text(strings.NewReader(`{\rtf1\pard\f0 Print {\webhidden\f0 only}\par}`))
+	if err != nil || r.String() != "Print only " {
+		t.Error("expected web hidden text to be included", err, r.String())
+	}
+}
+
+func TestTextContext(t *testing.T) {
+	doc := `{\rtf1\pard\f0 ` + strings.Repeat(`word\par `, 10000) + `}`
+	full, err := Text(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, err := TextContext(ctx, strings.NewReader(doc), Options{})
+	if err != context.Canceled || r == nil || r.Len() == 0 || r.Len() >= full.Len() || !strings.HasPrefix(full.String(), r.String()) {
+		t.Error("expected partial text", err, r.Len())
+	}
+
+	d, err := ConvertContext(ctx, strings.NewReader(doc), Options{})
+	if err != context.Canceled || d == nil || len(d.Text) == 0 {
+		t.Error("expected partial document", err, d)
+	}
+
+	r, err = TextContext(context.Background(), strings.NewReader(doc), Options{})
+	if err != nil || r.String() != full.String() {
+		t.Error("expected all text", err, r.Len())
+	}
+
+	// groups which are read at once stop too
+	for _, doc := range []string{
+		`{\rtf1{\*\unknown ` + strings.Repeat("x", 1<<20) + `}}`,
+		`{\rtf1{\pict\bin1048576 ` + strings.Repeat("x", 1<<20) + `}}`,
+	} {
+		counter := &countingReader{r: strings.NewReader(doc)}
+		if _, err := TextContext(ctx, counter, Options{}); err != context.Canceled || counter.n >= 1<<20 {
+			t.Error("expected conversion to stop while reading the group", err, counter.n)
+		}
+	}
+}
+
+// countingReader counts the bytes read
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
 func TestReadControl(t *testing.T) {
-	var s stack
-	var text bytes.Buffer
-	r := peekingReader.NewMemReader([]byte(""))
-	if err := readControl(r, &s, &text); err != io.EOF {
+	c := newConverter(peekingReader.NewMemReader([]byte("")), Options{})
+	if err := c.readControl(); err != io.EOF {
 		t.Error("expected error", err)
 	}
 
 	// no closing brace. Should error
-	r = peekingReader.NewMemReader([]byte("*\rsidtbl \rs"))
-	if err := readControl(r, &s, &text); err != io.EOF {
+	c.r = peekingReader.NewMemReader([]byte("*\rsidtbl \rs"))
+	if err := c.readControl(); err != io.EOF {
 		t.Error("expected error", err)
 	}
 
 	// no parameters found, no previous control to get params for
-	r = peekingReader.NewMemReader([]byte("*\rsidtbl \rs}"))
-	if err := readControl(r, &s, &text); err != nil {
+	c.r = peekingReader.NewMemReader([]byte("*\rsidtbl \rs}"))
+	if err := c.readControl(); err != nil {
 		t.Error("expected success", err)
 	}
 
 	// unicode
-	r = peekingReader.NewMemReader([]byte("'A9 "))
-	if err := readControl(r, &s, &text); err != nil || text.String() != "©" {
-		t.Error("expected success", err, text.String())
+	c.r = peekingReader.NewMemReader([]byte("'A9 "))
+	if err := c.readControl(); err != nil || c.text.String() != "©" {
+		t.Error("expected success", err, c.text.String())
 	}
-	text.Reset()
+	c.text.Reset()
 
-	r = peekingReader.NewMemReader([]byte("\\\\"))
-	if err := readControl(r, &s, &text); err != nil || text.String() != "\\" {
-		t.Error("expected success", err, text.String())
+	c.r = peekingReader.NewMemReader([]byte("\\\\"))
+	if err := c.readControl(); err != nil || c.text.String() != "\\" {
+		t.Error("expected success", err, c.text.String())
 	}
-	text.Reset()
+	c.text.Reset()
 
 	// carriage return
-	r = peekingReader.NewMemReader([]byte(`
+	c.r = peekingReader.NewMemReader([]byte(`
 `))
-	if err := readControl(r, &s, &text); err != nil || text.String() != "\n" {
-		t.Error("expected success", err, text.String())
+	if err := c.readControl(); err != nil || c.text.String() != "\n" {
+		t.Error("expected success", err, c.text.String())
 	}
-	text.Reset()
+	c.text.Reset()
 
 	// binary data error
-	r = peekingReader.NewMemReader([]byte(`bin412`))
-	if err := readControl(r, &s, &text); err != io.EOF {
-		t.Error("expected success", err, text.String())
+	c.r = peekingReader.NewMemReader([]byte(`bin412`))
+	if err := c.readControl(); err != io.EOF {
+		t.Error("expected success", err, c.text.String())
 	}
 
 	// binary data success
-	r = peekingReader.NewMemReader([]byte(`bin22 1234567890123456789012 hello}`))
-	if err := readControl(r, &s, &text); err != nil || text.String() != "" {
-		t.Error("expected success", err, text.String())
+	c.r = peekingReader.NewMemReader([]byte(`bin22 1234567890123456789012 hello}`))
+	if err := c.readControl(); err != nil || c.text.String() != "" {
+		t.Error("expected success", err, c.text.String())
 	}
 
 	// truncated parameter
-	r = peekingReader.NewMemReader([]byte(`f463 hi`))
-	if err := readControl(r, &s, &text); err != io.EOF {
-		t.Error("expected success", err, text.String())
+	c.r = peekingReader.NewMemReader([]byte(`f463 hi`))
+	if err := c.readControl(); err != io.EOF {
+		t.Error("expected success", err, c.text.String())
 	}
 }
 
@@ -113,6 +185,13 @@ func TestTokenizeControl(t *testing.T) {
 	if string(b) != " re often" {
 		t.Error("expected remaining string", string(b))
 	}
+
+	for _, hex := range []string{"'e9t", "'9dt", "'0at", "'ff1"} {
+		control, _, _ = tokenizeControl(peekingReader.NewMemReader([]byte(hex)))
+		if control != hex[:3] {
+			t.Error("expected hex control", hex, control)
+		}
+	}
 }
 
 type errorAtReader struct {
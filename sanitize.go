@@ -0,0 +1,159 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// personalDestinations are the destinations removed by Sanitize because
+// they identify people, computers or the history of a document
+var personalDestinations = destinationsUsed(usePersonal)
+
+// SanitizeOptions are what Sanitize removes besides personal information
+type SanitizeOptions struct {
+	StripObjects      bool // embedded objects are replaced by their result
+	StripLinkedImages bool // INCLUDEPICTURE fields and the file names of linked pictures are removed
+}
+
+// Sanitize is used to copy RTF from an io.Reader to an io.Writer without
+// personal information. The authors and other people of \info, comment
+// authors, \*\generator, \docvar variables, \*\datastore, \*\xmlnstbl, the
+// \*\rsidtbl and every \rsidN identifier are removed, while the authors of
+// the \*\revtbl are renamed Unknown so that revisions still refer to them.
+// The rest of the document is copied as it is
+func Sanitize(w io.Writer, r io.Reader, opts SanitizeOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	tokens, err := lex(data)
+	if err != nil {
+		return err
+	}
+	s := &sanitizer{data: data, tokens: tokens, ends: groupEnds(tokens), opts: opts, prev: -1, resume: make(map[int]int)}
+	s.sanitize()
+	return writeEdits(w, data, s.edits)
+}
+
+// sanitizer collects the edits which sanitize RTF data
+type sanitizer struct {
+	data   []byte
+	tokens []token
+	ends   []int // index of the end of the group started by each token
+	opts   SanitizeOptions
+	edits  []edit
+	prev   int         // index of the last token which is kept
+	resume map[int]int // tokens which are removed up to another token, for the results of objects
+}
+
+func (s *sanitizer) sanitize() {
+	for i := 0; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		if end, ok := s.resume[i]; ok {
+			s.edits = append(s.edits, edit{t.start, s.tokens[end].start, ""})
+			i = end - 1
+			continue
+		}
+		switch {
+		case t.kind == tokenGroupStart:
+			if end := s.sanitizeGroup(i); end >= 0 {
+				i = end
+				continue
+			}
+		case t.kind == tokenControl && isRsid(t.control):
+			s.remove(i, i)
+			continue
+		}
+		s.prev = i
+	}
+}
+
+// sanitizeGroup sanitizes the group started by the token at i. The index of
+// the last token handled is returned, or -1 when the group is kept
+func (s *sanitizer) sanitizeGroup(i int) int {
+	end := s.ends[i]
+	dest, ctrl := groupDest(s.tokens, i)
+	switch {
+	case personalDestinations[dest]:
+		s.remove(i, end)
+		return end
+	case dest == "revtbl":
+		for j := ctrl + 1; j < end; j++ {
+			if s.tokens[j].kind == tokenGroupStart && s.ends[j] < len(s.tokens) {
+				s.edits = append(s.edits, edit{s.tokens[j].end, s.tokens[s.ends[j]].start, "Unknown;"})
+				j = s.ends[j]
+			}
+		}
+		if end < len(s.tokens) {
+			s.prev = end
+		}
+		return end
+	case dest == "object" && s.opts.StripObjects:
+		for j := ctrl + 1; j < end; j++ {
+			if s.tokens[j].kind != tokenGroupStart {
+				continue
+			}
+			if resultDest, resultCtrl := groupDest(s.tokens, j); resultDest == "result" && end < len(s.tokens) {
+				s.edits = append(s.edits, edit{s.tokens[i].end, s.tokens[resultCtrl].end, ""})
+				s.resume[s.ends[j]] = end
+				s.prev = i
+				return resultCtrl
+			}
+			j = s.ends[j]
+		}
+		s.remove(i, end)
+		return end
+	case dest == "field" && s.opts.StripLinkedImages:
+		if strings.Contains(strings.ToUpper(s.text(i, end, "fldinst")), "INCLUDEPICTURE") {
+			s.remove(i, end)
+			return end
+		}
+	case dest == "sp" && s.opts.StripLinkedImages:
+		if name := strings.TrimSpace(s.text(i, end, "sn")); name == "pibName" || name == "pibFlags" {
+			s.remove(i, end)
+			return end
+		}
+	}
+	return -1
+}
+
+// remove removes the tokens from i to end. A space is kept when removing
+// them would join a control word to the text after them
+func (s *sanitizer) remove(i, end int) {
+	last := end
+	if last >= len(s.tokens) {
+		last = len(s.tokens) - 1
+	}
+	text := ""
+	if s.prev >= 0 && !s.tokens[s.prev].delimited(s.data) && last+1 < len(s.tokens) && s.tokens[last+1].kind == tokenText {
+		text = " "
+	}
+	s.edits = append(s.edits, edit{s.tokens[i].start, s.tokens[last].end, text})
+}
+
+// text returns the text of the first group with the destination inside the
+// group from i to end
+func (s *sanitizer) text(i, end int, dest string) string {
+	for j := i + 1; j < end && j < len(s.tokens); j++ {
+		if s.tokens[j].kind != tokenGroupStart {
+			continue
+		}
+		if d, _ := groupDest(s.tokens, j); d != dest {
+			continue
+		}
+		var b bytes.Buffer
+		for k := j + 1; k < s.ends[j] && k < len(s.tokens); k++ {
+			if s.tokens[k].kind == tokenText {
+				b.Write(s.data[s.tokens[k].start:s.tokens[k].end])
+			}
+		}
+		return b.String()
+	}
+	return ""
+}
+
+// isRsid returns whether the control word is a revision save identifier
+func isRsid(control string) bool {
+	return strings.HasSuffix(control, "rsidN") || control == "rsidrootN"
+}
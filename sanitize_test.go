@@ -0,0 +1,45 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	in := `{\rtf1\ansi{\*\generator Riched20 10.0;}{\info{\title Report}{\author John Smith}{\operator Jane Doe}}` +
+		`{\*\rsidtbl \rsid123\rsid456}{\*\revtbl {Unknown;}{John Smith;}}{\*\xmlnstbl {\xmlns1 http://example.com}}` +
+		`{\*\docvar {client}{ACME}}{\*\datastore 0105}\pard\plain\b\insrsid123 Hello\charrsid456 {\deleted\revauth1 old}` +
+		`{\*\atnid JS}{\*\atnauthor John Smith}\chatn{\*\annotation note}\par}`
+	expected := `{\rtf1\ansi{\info{\title Report}}{\*\revtbl {Unknown;}{Unknown;}}` +
+		`\pard\plain\b Hello{\deleted\revauth1 old}\chatn{\*\annotation note}\par}`
+	var b bytes.Buffer
+	if err := Sanitize(&b, strings.NewReader(in), SanitizeOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestSanitizeObjectsAndImages(t *testing.T) {
+	in := `{\rtf1 a{\object\objemb{\*\objclass Word.Document.12}{\*\objdata 0105}{\result {\pict\pngblip 89}}}` +
+		`{\field{\*\fldinst { INCLUDEPICTURE "C:\\\\Users\\\\john\\\\a.png" \\d}}{\fldrslt {\pict 89}}}` +
+		`{\shp{\*\shpinst{\sp{\sn pibName}{\sv C:\\\\Users\\\\john\\\\b.png}}{\sp{\sn fLine}{\sv 0}}}}b\par}`
+	expected := `{\rtf1 a{{\pict\pngblip 89}}{\shp{\*\shpinst{\sp{\sn fLine}{\sv 0}}}}b\par}`
+	var b bytes.Buffer
+	if err := Sanitize(&b, strings.NewReader(in), SanitizeOptions{StripObjects: true, StripLinkedImages: true}); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	b.Reset()
+	if err := Sanitize(&b, strings.NewReader(in), SanitizeOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != in {
+		t.Errorf("expected objects and images to be kept, got %q", b.String())
+	}
+}
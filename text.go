@@ -0,0 +1,182 @@
+package rtf2txt
+
+import (
+	"encoding/hex"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// textChar is a character of the text of RTF data with the range of the data
+// it was read from
+type textChar struct {
+	r          rune
+	start, end int64
+	fixed      bool   // break written by a control word or a change of destination, which can't be rewritten
+	afterWord  bool   // follows a control word without a delimiter
+	data       bool   // byte of the hex or binary data of a destination rather than text
+	uc         int    // number of fallback characters after \uN in the group of the character
+	dest       string // destination of the group of the character
+}
+
+// textMode is how the text of a destination is read by textChars
+type textMode int
+
+const (
+	textSkip textMode = iota // left out
+	textRead                 // read as text
+	textData                 // read as hex or binary data
+)
+
+// textGroup is the state of a group while the text of RTF data is read
+type textGroup struct {
+	dest  string // destination of the group, or "" for the document
+	first bool   // no control has been read in the group yet
+	star  bool   // \* started the group
+	uc    int    // number of fallback characters after \uN
+}
+
+// textChars returns the characters of the text of RTF data, reading each
+// destination as mode returns. Breaks are added where destinations start and
+// end so that matches don't span them
+func textChars(data []byte, tokens []token, mode func(dest string) textMode) []textChar {
+	var chars []textChar
+	groups := []textGroup{{uc: 1}}
+	fallback := 0 // fallback characters of \uN still to be skipped
+	nibble := -1  // offset of the first hex digit of a byte of data
+	addBreak := func(start int64) {
+		if len(chars) > 0 && !chars[len(chars)-1].fixed {
+			chars = append(chars, textChar{start: start, end: start, fixed: true})
+		}
+	}
+	add := func(r rune, start, end int64, afterWord bool) {
+		g := groups[len(groups)-1]
+		if mode(g.dest) != textRead {
+			return
+		}
+		if fallback > 0 { // the fallback is rewritten with its \uN
+			fallback--
+			if last := &chars[len(chars)-1]; last.end == start {
+				last.end = end
+			}
+			return
+		}
+		if last := len(chars) - 1; last >= 0 && utf16.IsSurrogate(chars[last].r) && chars[last].end == start {
+			if combined := utf16.DecodeRune(chars[last].r, r); combined != unicode.ReplacementChar {
+				chars[last].r, chars[last].end = combined, end
+				return
+			}
+		}
+		chars = append(chars, textChar{r: r, start: start, end: end, afterWord: afterWord, uc: g.uc, dest: g.dest})
+	}
+
+	for i, t := range tokens {
+		g := &groups[len(groups)-1]
+		afterWord := i > 0 && !tokens[i-1].delimited(data)
+		switch t.kind {
+		case tokenGroupStart:
+			groups = append(groups, textGroup{dest: g.dest, first: true, uc: g.uc})
+			fallback = 0
+			continue
+		case tokenGroupEnd:
+			if len(groups) > 1 {
+				if g.dest != groups[len(groups)-2].dest {
+					addBreak(t.start)
+				}
+				groups = groups[:len(groups)-1]
+			}
+			fallback, nibble = 0, -1
+			continue
+		case tokenBinary:
+			if mode(g.dest) == textData {
+				for o := t.start; o < t.end; o++ {
+					chars = append(chars, textChar{r: rune(data[o]), start: o, end: o + 1, data: true})
+				}
+			}
+			continue
+		case tokenText:
+			if mode(g.dest) == textData {
+				for o := t.start; o < t.end; o++ {
+					if !isHexDigit(data[o]) {
+						continue
+					}
+					if nibble < 0 {
+						nibble = int(o)
+						continue
+					}
+					var b [1]byte
+					hex.Decode(b[:], []byte{data[nibble], data[o]})
+					chars = append(chars, textChar{r: rune(b[0]), start: int64(nibble), end: o + 1, data: true})
+					nibble = -1
+				}
+				continue
+			}
+			for o := t.start; o < t.end; o++ {
+				if b := data[o]; b != '\r' && b != '\n' {
+					add(rune(b), o, o+1, afterWord && o == t.start)
+				}
+			}
+			continue
+		}
+
+		first, star := g.first, g.star
+		g.first, g.star = false, false
+		if t.control == "*" {
+			g.star = first
+			g.first = first
+			continue
+		}
+		if first || star {
+			if _, dest := knownControl(t.control); dest || star {
+				if mode(g.dest) != textSkip || mode(t.control) != textSkip {
+					addBreak(t.start)
+				}
+				g.dest = t.control
+				continue
+			}
+		}
+		switch {
+		case t.control == "ucN":
+			g.uc = t.num
+		case t.control == "uN":
+			num := t.num
+			if num < 0 {
+				num += 65536
+			}
+			add(rune(num), t.start, t.end, false)
+			if mode(g.dest) == textRead {
+				fallback = g.uc
+			}
+		case t.control[0] == '\'':
+			if isUnicode, u := getUnicode(t.control); isUnicode && u != "" {
+				add([]rune(u)[0], t.start, t.end, false)
+			}
+		case t.control == "\\" || t.control == "{" || t.control == "}":
+			add(rune(t.control[0]), t.start, t.end, false)
+		default:
+			if symbol, found := convertSymbol(t.control); found && symbol != "" && mode(g.dest) == textRead {
+				addBreak(t.start)
+			}
+		}
+	}
+	return chars
+}
+
+// escapeText escapes text which replaces characters read with a \ucN of uc,
+// so that the fallback of \uN matches it
+func escapeText(text string, uc int) string {
+	escaped := escapeRTF(text)
+	if uc != 1 && strings.Contains(escaped, `\u`) {
+		return `{\uc1 ` + escaped + "}"
+	}
+	return escaped
+}
+
+// rewrite returns the edit which writes text in place of the character
+func (c textChar) rewrite(text string) edit {
+	escaped := escapeText(text, c.uc)
+	if c.afterWord && escaped != "" {
+		escaped = " " + escaped // keep the control word before from running into the text
+	}
+	return edit{c.start, c.end, escaped}
+}
@@ -0,0 +1,358 @@
+package rtf2txt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// tnefSignature starts every TNEF stream
+const tnefSignature = 0x223e9f78
+
+// TNEF attribute levels and the attributes which are read
+const (
+	tnefLevelMessage    = 1
+	tnefLevelAttachment = 2
+
+	attSubject        = 0x00018004
+	attMessageClass   = 0x00078008
+	attBody           = 0x0002800c
+	attAttachData     = 0x0006800f
+	attAttachTitle    = 0x00018010
+	attAttachRendData = 0x00069002
+	attMAPIProps      = 0x00069003
+	attAttachment     = 0x00069005
+)
+
+// MAPI property types
+const (
+	ptShort    = 0x0002
+	ptLong     = 0x0003
+	ptFloat    = 0x0004
+	ptDouble   = 0x0005
+	ptCurrency = 0x0006
+	ptAppTime  = 0x0007
+	ptError    = 0x000a
+	ptBoolean  = 0x000b
+	ptObject   = 0x000d
+	ptI8       = 0x0014
+	ptString8  = 0x001e
+	ptUnicode  = 0x001f
+	ptSysTime  = 0x0040
+	ptCLSID    = 0x0048
+	ptBinary   = 0x0102
+	ptMulti    = 0x1000
+)
+
+// MAPI properties which are read
+const (
+	prSubject            = 0x0037
+	prMessageClass       = 0x001a
+	prBody               = 0x1000
+	prRTFCompressed      = 0x1009
+	prAttachDataBin      = 0x3701
+	prAttachFilename     = 0x3704
+	prAttachLongFilename = 0x3707
+	prAttachMIMETag      = 0x370e
+)
+
+// TNEF is an Outlook message read from a TNEF stream, such as a winmail.dat
+// attachment
+type TNEF struct {
+	Subject      string
+	MessageClass string
+	Body         string // plain text body
+	RTF          []byte // decompressed RTF body
+	Attachments  []Attachment
+}
+
+// Attachment is a file attached to a TNEF message
+type Attachment struct {
+	Name     string
+	MIMEType string
+	Data     []byte
+}
+
+// ErrNoRTF is returned by TNEF.Text when the message has no RTF body
+var ErrNoRTF = errors.New("TNEF message has no RTF body")
+
+// ReadTNEF is used to read the message and attachments from an io.Reader
+// containing a TNEF stream. The compressed RTF body is decompressed
+func ReadTNEF(r io.Reader) (*TNEF, error) {
+	br := bufio.NewReader(r)
+	var header struct {
+		Signature uint32
+		Key       uint16
+	}
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("Invalid TNEF header. %w", err)
+	}
+	if header.Signature != tnefSignature {
+		return nil, fmt.Errorf("Invalid TNEF signature 0x%08x", header.Signature)
+	}
+
+	t := &TNEF{}
+	for {
+		level, err := br.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		id, data, err := readTNEFAttribute(br)
+		if err != nil {
+			return nil, err
+		}
+		if level == tnefLevelMessage {
+			err = t.handleMessageAttribute(id, data)
+		} else if level == tnefLevelAttachment {
+			err = t.handleAttachmentAttribute(id, data)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Text is used to convert the RTF body of the message into plain text
+func (t *TNEF) Text(opts Options) (*bytes.Buffer, error) {
+	if t.RTF == nil {
+		return nil, ErrNoRTF
+	}
+	return TextWithOptions(bytes.NewReader(t.RTF), opts)
+}
+
+// readTNEFAttribute reads the ID and the data of an attribute after its
+// level and checks its checksum
+func readTNEFAttribute(r io.Reader) (uint32, []byte, error) {
+	var header struct {
+		ID, Length uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return 0, nil, fmt.Errorf("TNEF attribute is truncated. %w", err)
+	}
+	var data bytes.Buffer // grows with the data actually read rather than the length claimed
+	if _, err := io.CopyN(&data, r, int64(header.Length)); err != nil {
+		return 0, nil, fmt.Errorf("TNEF attribute 0x%08x is truncated. %w", header.ID, err)
+	}
+	var checksum uint16
+	if err := binary.Read(r, binary.LittleEndian, &checksum); err != nil {
+		return 0, nil, fmt.Errorf("TNEF attribute 0x%08x is truncated. %w", header.ID, err)
+	}
+	var sum uint16
+	for _, b := range data.Bytes() {
+		sum += uint16(b)
+	}
+	if sum != checksum {
+		return 0, nil, fmt.Errorf("TNEF attribute 0x%08x has checksum 0x%04x instead of 0x%04x", header.ID, sum, checksum)
+	}
+	return header.ID, data.Bytes(), nil
+}
+
+func (t *TNEF) handleMessageAttribute(id uint32, data []byte) error {
+	switch id {
+	case attSubject:
+		t.Subject = tnefString(data)
+	case attMessageClass:
+		t.MessageClass = tnefString(data)
+	case attBody:
+		t.Body = tnefString(data)
+	case attMAPIProps:
+		return readMAPIProps(data, func(prop uint16, value []byte, unicode bool) error {
+			switch prop {
+			case prSubject:
+				t.Subject = mapiString(value, unicode)
+			case prMessageClass:
+				t.MessageClass = mapiString(value, unicode)
+			case prBody:
+				t.Body = mapiString(value, unicode)
+			case prRTFCompressed:
+				rtf, err := Decompress(bytes.NewReader(value))
+				if err != nil {
+					return err
+				}
+				t.RTF = rtf
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+func (t *TNEF) handleAttachmentAttribute(id uint32, data []byte) error {
+	if id == attAttachRendData { // starts each attachment
+		t.Attachments = append(t.Attachments, Attachment{})
+		return nil
+	}
+	if len(t.Attachments) == 0 {
+		return fmt.Errorf("TNEF attachment attribute 0x%08x comes before the attachment", id)
+	}
+	a := &t.Attachments[len(t.Attachments)-1]
+	switch id {
+	case attAttachTitle:
+		if a.Name == "" {
+			a.Name = tnefString(data)
+		}
+	case attAttachData:
+		a.Data = data
+	case attAttachment:
+		return readMAPIProps(data, func(prop uint16, value []byte, unicode bool) error {
+			switch prop {
+			case prAttachLongFilename:
+				a.Name = mapiString(value, unicode)
+			case prAttachFilename:
+				if a.Name == "" {
+					a.Name = mapiString(value, unicode)
+				}
+			case prAttachMIMETag:
+				a.MIMEType = mapiString(value, unicode)
+			case prAttachDataBin:
+				if a.Data == nil {
+					a.Data = value
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// readMAPIProps calls handle with each single valued property of a list of
+// MAPI properties
+func readMAPIProps(data []byte, handle func(prop uint16, value []byte, unicode bool) error) error {
+	p := &mapiReader{data: data}
+	count := p.uint32()
+	for i := uint32(0); i < count && p.err == nil; i++ {
+		typ, prop := p.uint16(), p.uint16()
+		if prop >= 0x8000 { // named property
+			p.skip(16) // GUID
+			if kind := p.uint32(); kind == 0 {
+				p.skip(4)
+			} else {
+				p.skip(int(p.uint32()))
+				p.align()
+			}
+		}
+		values := uint32(1)
+		if typ&ptMulti != 0 {
+			values = p.uint32()
+		}
+		base := typ &^ ptMulti
+		if typ&ptMulti == 0 && (base == ptString8 || base == ptUnicode || base == ptBinary || base == ptObject) {
+			values = p.uint32() // variable length values are always counted
+		}
+		for v := uint32(0); v < values && p.err == nil; v++ {
+			value := p.value(base)
+			if p.err == nil && typ&ptMulti == 0 && v == 0 {
+				if base == ptObject && len(value) >= 16 {
+					value = value[16:] // interface identifier
+				}
+				if err := handle(prop, value, base == ptUnicode); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if p.err != nil {
+		return fmt.Errorf("Invalid MAPI properties. %w", p.err)
+	}
+	return nil
+}
+
+// mapiReader reads the values of MAPI properties, keeping the first error
+type mapiReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (p *mapiReader) bytes(n int) []byte {
+	if p.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(p.data)-p.pos {
+		p.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := p.data[p.pos : p.pos+n]
+	p.pos += n
+	return b
+}
+
+func (p *mapiReader) skip(n int) {
+	p.bytes(n)
+}
+
+func (p *mapiReader) align() {
+	if rem := p.pos % 4; rem != 0 && p.err == nil {
+		n := 4 - rem
+		if n > len(p.data)-p.pos {
+			n = len(p.data) - p.pos
+		}
+		p.skip(n)
+	}
+}
+
+func (p *mapiReader) uint16() uint16 {
+	if b := p.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (p *mapiReader) uint32() uint32 {
+	if b := p.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+// value reads a value of the type
+func (p *mapiReader) value(typ uint16) []byte {
+	switch typ {
+	case ptShort, ptLong, ptFloat, ptError, ptBoolean:
+		return p.bytes(4)
+	case ptDouble, ptCurrency, ptAppTime, ptI8, ptSysTime:
+		return p.bytes(8)
+	case ptCLSID:
+		return p.bytes(16)
+	case ptString8, ptUnicode, ptBinary, ptObject:
+		b := p.bytes(int(p.uint32()))
+		p.align()
+		return b
+	default:
+		p.err = fmt.Errorf("unknown MAPI property type 0x%04x", typ)
+		return nil
+	}
+}
+
+// tnefString decodes a zero terminated string from a TNEF attribute
+func tnefString(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+	runes := make([]rune, len(data)) // the OEM code page is read as ISO-8859-1, like \'hh
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// mapiString decodes a zero terminated PT_STRING8 or PT_UNICODE value
+func mapiString(data []byte, unicode bool) string {
+	if !unicode {
+		return tnefString(data)
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+}
@@ -0,0 +1,148 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unicode/utf16"
+)
+
+// tnefWriter builds TNEF streams for tests
+type tnefWriter struct {
+	bytes.Buffer
+}
+
+func newTNEFWriter() *tnefWriter {
+	w := &tnefWriter{}
+	binary.Write(w, binary.LittleEndian, uint32(tnefSignature))
+	binary.Write(w, binary.LittleEndian, uint16(1))
+	return w
+}
+
+func (w *tnefWriter) attribute(level byte, id uint32, data []byte) {
+	w.WriteByte(level)
+	binary.Write(w, binary.LittleEndian, id)
+	binary.Write(w, binary.LittleEndian, uint32(len(data)))
+	w.Write(data)
+	var sum uint16
+	for _, b := range data {
+		sum += uint16(b)
+	}
+	binary.Write(w, binary.LittleEndian, sum)
+}
+
+// mapiProps encodes properties, which are either uint32, string for
+// PT_UNICODE or []byte for PT_BINARY
+func mapiProps(props map[uint16]interface{}, order ...uint16) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, uint32(len(order)))
+	pad := func() {
+		for b.Len()%4 != 0 {
+			b.WriteByte(0)
+		}
+	}
+	for _, prop := range order {
+		switch v := props[prop].(type) {
+		case uint32:
+			binary.Write(&b, binary.LittleEndian, []uint16{ptLong, prop})
+			binary.Write(&b, binary.LittleEndian, v)
+		case string:
+			units := append(utf16.Encode([]rune(v)), 0)
+			binary.Write(&b, binary.LittleEndian, []uint16{ptUnicode, prop})
+			binary.Write(&b, binary.LittleEndian, []uint32{1, uint32(len(units) * 2)})
+			binary.Write(&b, binary.LittleEndian, units)
+			pad()
+		case []byte:
+			binary.Write(&b, binary.LittleEndian, []uint16{ptBinary, prop})
+			binary.Write(&b, binary.LittleEndian, []uint32{1, uint32(len(v))})
+			b.Write(v)
+			pad()
+		}
+	}
+	return b.Bytes()
+}
+
+func TestReadTNEF(t *testing.T) {
+	w := newTNEFWriter()
+	w.attribute(tnefLevelMessage, attMessageClass, []byte("IPM.Microsoft Mail.Note\x00"))
+	w.attribute(tnefLevelMessage, attSubject, []byte("Caf\xe9\x00"))
+	w.attribute(tnefLevelMessage, attMAPIProps, mapiProps(map[uint16]interface{}{
+		0x0e07:          uint32(1),
+		prSubject:       "Café ☕",
+		prRTFCompressed: compressedExample,
+	}, 0x0e07, prSubject, prRTFCompressed))
+	w.attribute(tnefLevelAttachment, attAttachRendData, make([]byte, 14))
+	w.attribute(tnefLevelAttachment, attAttachTitle, []byte("REPORT~1.TXT\x00"))
+	w.attribute(tnefLevelAttachment, attAttachData, []byte("report"))
+	w.attribute(tnefLevelAttachment, attAttachment, mapiProps(map[uint16]interface{}{
+		prAttachLongFilename: "report for 2024.txt",
+		prAttachMIMETag:      "text/plain",
+	}, prAttachLongFilename, prAttachMIMETag))
+	w.attribute(tnefLevelAttachment, attAttachRendData, make([]byte, 14))
+	w.attribute(tnefLevelAttachment, attAttachment, mapiProps(map[uint16]interface{}{
+		prAttachFilename: "a.bin",
+		prAttachDataBin:  []byte{1, 2, 3},
+	}, prAttachFilename, prAttachDataBin))
+
+	tnef, err := ReadTNEF(bytes.NewReader(w.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tnef.MessageClass != "IPM.Microsoft Mail.Note" || tnef.Subject != "Café ☕" {
+		t.Error("expected message attributes", tnef.MessageClass, tnef.Subject)
+	}
+	if text, err := tnef.Text(Options{}); err != nil || text.String() != "hello world" {
+		t.Error("expected rtf body", text, err)
+	}
+	if len(tnef.Attachments) != 2 {
+		t.Fatal("expected attachments", tnef.Attachments)
+	}
+	if a := tnef.Attachments[0]; a.Name != "report for 2024.txt" || a.MIMEType != "text/plain" || string(a.Data) != "report" {
+		t.Error("expected first attachment", a)
+	}
+	if a := tnef.Attachments[1]; a.Name != "a.bin" || !bytes.Equal(a.Data, []byte{1, 2, 3}) {
+		t.Error("expected second attachment", a)
+	}
+
+	if _, err := (&TNEF{}).Text(Options{}); err != ErrNoRTF {
+		t.Error("expected no rtf", err)
+	}
+}
+
+func TestReadTNEFErrors(t *testing.T) {
+	if _, err := ReadTNEF(bytes.NewReader([]byte{1, 2, 3, 4, 5, 6})); err == nil {
+		t.Error("expected signature error")
+	}
+
+	w := newTNEFWriter()
+	w.attribute(tnefLevelMessage, attSubject, []byte("subject\x00"))
+	data := w.Bytes()
+	data[len(data)-1]++
+	if _, err := ReadTNEF(bytes.NewReader(data)); err == nil {
+		t.Error("expected checksum error")
+	}
+	if _, err := ReadTNEF(bytes.NewReader(data[:len(data)-4])); err == nil {
+		t.Error("expected truncated error")
+	}
+
+	w = newTNEFWriter()
+	w.attribute(tnefLevelAttachment, attAttachTitle, []byte("a.txt\x00"))
+	if _, err := ReadTNEF(bytes.NewReader(w.Bytes())); err == nil {
+		t.Error("expected attachment error")
+	}
+
+	w = newTNEFWriter()
+	w.attribute(tnefLevelMessage, attMAPIProps, []byte{5, 0, 0, 0, 3, 0})
+	if _, err := ReadTNEF(bytes.NewReader(w.Bytes())); err == nil {
+		t.Error("expected MAPI properties error")
+	}
+
+	corrupt := append([]byte{}, compressedExample...)
+	corrupt[20] ^= 0xff
+	w = newTNEFWriter()
+	w.attribute(tnefLevelMessage, attMAPIProps, mapiProps(map[uint16]interface{}{prRTFCompressed: corrupt}, prRTFCompressed))
+	if _, err := ReadTNEF(bytes.NewReader(w.Bytes())); !errors.Is(err, ErrCompressedCRC) {
+		t.Error("expected crc error", err)
+	}
+}
@@ -0,0 +1,145 @@
+package rtf2txt
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/EndFirstCorp/peekingReader"
+)
+
+// tokenKind is the kind of a token of RTF data
+type tokenKind int
+
+const (
+	tokenText       tokenKind = iota // text, including new lines
+	tokenGroupStart                  // {
+	tokenGroupEnd                    // }
+	tokenControl                     // control word with its delimiting space, or control symbol
+	tokenBinary                      // data of \binN
+)
+
+// token is a part of RTF data which can be copied or rewritten as a whole
+type token struct {
+	kind       tokenKind
+	start, end int64  // offsets of the token in the data
+	control    string // name of a control like tokenizeControl returns it, or the character of a control symbol
+	num        int    // parameter of a control word, or -1
+}
+
+// lex splits RTF data into tokens using the same rules as the converter.
+// Braces don't have to be balanced. The tokens read before an error are
+// returned with it
+func lex(data []byte) ([]token, error) {
+	r := newPositionReader(peekingReader.NewMemReader(data))
+	size := int64(len(data))
+	var tokens []token
+	var control string
+	depth := 0
+	fail := func(err error) ([]token, error) {
+		return tokens, &ParseError{Offset: r.offset, Line: r.line, Column: r.column, Control: control, Depth: depth, Err: err}
+	}
+	for r.offset < size {
+		start := r.offset
+		switch data[start] {
+		case '{':
+			r.ReadByte()
+			depth++
+			tokens = append(tokens, token{kind: tokenGroupStart, start: start, end: r.offset, num: -1})
+		case '}':
+			r.ReadByte()
+			if depth > 0 {
+				depth--
+			}
+			tokens = append(tokens, token{kind: tokenGroupEnd, start: start, end: r.offset, num: -1})
+		case '\\':
+			r.ReadByte()
+			name, num, err := tokenizeControl(r)
+			if err != nil {
+				return fail(err)
+			}
+			if name == "" { // control symbol such as \{ or \~
+				if _, err := r.ReadByte(); err != nil {
+					return fail(err)
+				}
+				name = string(data[start+1])
+			} else if isLetter(name[0]) && r.offset < size && data[r.offset] == ' ' {
+				r.ReadByte()
+			}
+			control = name
+			tokens = append(tokens, token{kind: tokenControl, start: start, end: r.offset, control: name, num: num})
+			if name != "binN" {
+				continue
+			}
+			if num < 0 {
+				return fail(fmt.Errorf("%w. Invalid binary data length", ErrMalformedControl))
+			}
+			binStart := r.offset
+			if int64(num) > size-binStart {
+				r.ReadBytes(int(size - binStart))
+				return fail(io.ErrUnexpectedEOF)
+			}
+			r.ReadBytes(num)
+			tokens = append(tokens, token{kind: tokenBinary, start: binStart, end: r.offset, num: -1})
+		default:
+			end := start + 1
+			for end < size && data[end] != '\\' && data[end] != '{' && data[end] != '}' {
+				end++
+			}
+			r.ReadBytes(int(end - start))
+			tokens = append(tokens, token{kind: tokenText, start: start, end: end, num: -1})
+		}
+	}
+	return tokens, nil
+}
+
+// groupDest returns the destination of the group started by the token at i
+// and the index of its control word, or "" and i when it has none
+func groupDest(tokens []token, i int) (string, int) {
+	j := i + 1
+	if j < len(tokens) && tokens[j].kind == tokenControl && tokens[j].control == "*" {
+		j++
+	}
+	if j < len(tokens) && tokens[j].isWord() {
+		return tokens[j].control, j
+	}
+	return "", i
+}
+
+// groupEnds returns the index of the token which ends the group started by
+// each token, or len(tokens) for groups which aren't closed
+func groupEnds(tokens []token) []int {
+	ends := make([]int, len(tokens))
+	var open []int
+	for i, t := range tokens {
+		switch t.kind {
+		case tokenGroupStart:
+			open = append(open, i)
+		case tokenGroupEnd:
+			if len(open) > 0 {
+				ends[open[len(open)-1]] = i
+				open = open[:len(open)-1]
+			}
+		}
+	}
+	for _, i := range open {
+		ends[i] = len(tokens)
+	}
+	return ends
+}
+
+// isLetter returns whether b can be part of the name of a control word
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// isWord returns whether the token is a control word rather than a control
+// symbol
+func (t token) isWord() bool {
+	return t.kind == tokenControl && t.control != "" && isLetter(t.control[0])
+}
+
+// delimited returns whether a control word ends with a space, so that text
+// can follow it
+func (t token) delimited(data []byte) bool {
+	return !t.isWord() || data[t.end-1] == ' '
+}
@@ -0,0 +1,40 @@
+package rtf2txt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	data := []byte("{\\rtf1 a\\'e9\\{\\bin3 {}}\\b0;\r\n}")
+	tokens, err := lex(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		kind tokenKind
+		raw  string
+	}{
+		{tokenGroupStart, "{"}, {tokenControl, `\rtf1 `}, {tokenText, "a"}, {tokenControl, `\'e9`}, {tokenControl, `\{`},
+		{tokenControl, `\bin3 `}, {tokenBinary, "{}}"}, {tokenControl, `\b0`}, {tokenText, ";\r\n"}, {tokenGroupEnd, "}"},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, got %d", len(expected), len(tokens))
+	}
+	for i, e := range expected {
+		if tokens[i].kind != e.kind || string(data[tokens[i].start:tokens[i].end]) != e.raw {
+			t.Errorf("expected token %d to be %q, got %q", i, e.raw, data[tokens[i].start:tokens[i].end])
+		}
+	}
+	if tokens[1].control != "rtfN" || tokens[1].num != 1 || tokens[7].delimited(data) || !tokens[1].delimited(data) {
+		t.Error("expected control names, numbers and delimiters", tokens[1], tokens[7])
+	}
+
+	var parseErr *ParseError
+	if _, err := lex([]byte(`{\rtf1 \bin9 ab}`)); !errors.As(err, &parseErr) || !errors.Is(err, ErrTruncated) || parseErr.Control != "binN" {
+		t.Error("expected truncated binary data", err)
+	}
+	if _, err := lex([]byte(`{\rtf1 \99}`)); !errors.Is(err, ErrMalformedControl) {
+		t.Error("expected malformed control", err)
+	}
+}
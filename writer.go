@@ -0,0 +1,263 @@
+package rtf2txt
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// DefaultFont is the font of runs which don't have one
+const DefaultFont = "Times New Roman"
+
+// defaultSize is the size in points of runs which don't have one
+const defaultSize = 12
+
+// defaultCellWidth is the width in twips of table cells which don't have one
+const defaultCellWidth = 2880
+
+// Style is the character formatting of a Run
+type Style struct {
+	Font                            string // DefaultFont when empty
+	Size                            int    // in points, 12 when 0
+	Bold, Italic, Underline, Strike bool
+	Color                           color.Color // the default color when nil
+	Link                            string      // URL of a hyperlink
+}
+
+// Run is text written with one Style
+type Run struct {
+	Text string
+	Style
+}
+
+// Alignment is the horizontal alignment of a Paragraph
+type Alignment int
+
+// Paragraph alignments
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+	AlignJustify
+)
+
+// Block is a Paragraph or a Table
+type Block interface {
+	writeRTF(w *rtfWriter)
+}
+
+// Paragraph is a paragraph of runs. New lines in the text of a run are line
+// breaks within the paragraph
+type Paragraph struct {
+	Runs  []Run
+	Align Alignment
+}
+
+// Table is a table of rows of cells
+type Table struct {
+	Rows [][]Cell
+}
+
+// Cell is a cell of a Table
+type Cell struct {
+	Runs  []Run
+	Width int // in twips, 2880 (2 inches) when 0
+}
+
+// Write is used to write an RTF document containing the blocks to an
+// io.Writer
+func Write(w io.Writer, blocks []Block) error {
+	rw := &rtfWriter{fonts: []string{DefaultFont}, colors: []color.RGBA{}}
+	var body strings.Builder
+	rw.w = &body
+	for _, b := range blocks {
+		b.writeRTF(rw)
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{\rtf1\ansi\ansicpg1252\deff0\uc1` + "\n")
+	bw.WriteString(`{\fonttbl`)
+	for i, font := range rw.fonts {
+		fmt.Fprintf(bw, `{\f%d\fnil %s;}`, i, escapeRTF(font))
+	}
+	bw.WriteString("}\n")
+	if len(rw.colors) > 0 {
+		bw.WriteString(`{\colortbl;`)
+		for _, c := range rw.colors {
+			fmt.Fprintf(bw, `\red%d\green%d\blue%d;`, c.R, c.G, c.B)
+		}
+		bw.WriteString("}\n")
+	}
+	bw.WriteString(body.String())
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// WriteRuns is used to write an RTF document containing styled runs to an
+// io.Writer. Each new line in the text of the runs starts a new paragraph
+func WriteRuns(w io.Writer, runs []Run) error {
+	p := &Paragraph{}
+	blocks := []Block{p}
+	for _, r := range runs {
+		lines := strings.Split(r.Text, "\n")
+		for i, line := range lines {
+			if i > 0 {
+				p = &Paragraph{}
+				blocks = append(blocks, p)
+			}
+			if line != "" {
+				p.Runs = append(p.Runs, Run{Text: line, Style: r.Style})
+			}
+		}
+	}
+	return Write(w, blocks)
+}
+
+// rtfWriter writes the body of a document while collecting the fonts and
+// colors it uses
+type rtfWriter struct {
+	w      *strings.Builder
+	fonts  []string
+	colors []color.RGBA
+}
+
+func (p *Paragraph) writeRTF(w *rtfWriter) {
+	w.w.WriteString(`\pard\plain`)
+	switch p.Align {
+	case AlignCenter:
+		w.w.WriteString(`\qc`)
+	case AlignRight:
+		w.w.WriteString(`\qr`)
+	case AlignJustify:
+		w.w.WriteString(`\qj`)
+	default:
+		w.w.WriteString(`\ql`)
+	}
+	for _, r := range p.Runs {
+		w.writeRun(r)
+	}
+	w.w.WriteString("\\par\n")
+}
+
+func (t *Table) writeRTF(w *rtfWriter) {
+	for _, row := range t.Rows {
+		w.w.WriteString(`\trowd\trgaph108`)
+		x := 0
+		for _, cell := range row {
+			width := cell.Width
+			if width <= 0 {
+				width = defaultCellWidth
+			}
+			x += width
+			fmt.Fprintf(w.w, `\cellx%d`, x)
+		}
+		w.w.WriteString("\n")
+		for _, cell := range row {
+			w.w.WriteString(`\pard\plain\intbl`)
+			for _, r := range cell.Runs {
+				w.writeRun(r)
+			}
+			w.w.WriteString("\\cell\n")
+		}
+		w.w.WriteString("\\row\n")
+	}
+}
+
+// writeRun writes the run as a group. Hyperlinks are written as HYPERLINK
+// fields
+func (w *rtfWriter) writeRun(r Run) {
+	if r.Link != "" {
+		link := strings.ReplaceAll(r.Link, `"`, "%22")
+		fmt.Fprintf(w.w, `{\field{\*\fldinst{HYPERLINK "%s"}}{\fldrslt`, escapeRTF(link))
+		if r.Color == nil {
+			r.Color = color.RGBA{0, 0, 0xff, 0xff}
+		}
+		r.Underline = true
+	}
+	w.w.WriteString("{")
+	if r.Bold {
+		w.w.WriteString(`\b`)
+	}
+	if r.Italic {
+		w.w.WriteString(`\i`)
+	}
+	if r.Underline {
+		w.w.WriteString(`\ul`)
+	}
+	if r.Strike {
+		w.w.WriteString(`\strike`)
+	}
+	if r.Color != nil {
+		fmt.Fprintf(w.w, `\cf%d`, w.color(r.Color))
+	}
+	size := r.Size
+	if size <= 0 {
+		size = defaultSize
+	}
+	fmt.Fprintf(w.w, `\f%d\fs%d `, w.font(r.Font), size*2) // text after \fsN is read by Text
+	w.w.WriteString(escapeRTF(r.Text))
+	w.w.WriteString("}")
+	if r.Link != "" {
+		w.w.WriteString("}}")
+	}
+}
+
+// font returns the index of the font in the font table
+func (w *rtfWriter) font(name string) int {
+	if name == "" {
+		return 0
+	}
+	for i, f := range w.fonts {
+		if f == name {
+			return i
+		}
+	}
+	w.fonts = append(w.fonts, name)
+	return len(w.fonts) - 1
+}
+
+// color returns the index of the color in the color table. Index 0 is the
+// default color
+func (w *rtfWriter) color(c color.Color) int {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	rgba.A = 0xff
+	for i, existing := range w.colors {
+		if existing == rgba {
+			return i + 1
+		}
+	}
+	w.colors = append(w.colors, rgba)
+	return len(w.colors)
+}
+
+// escapeRTF escapes text for RTF. Characters in ISO-8859-1 are written as
+// \'hh and others as \uN with ? as the fallback. New lines are line breaks
+func escapeRTF(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '{' || r == '}':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\line `)
+		case r == '\t':
+			b.WriteString(`\tab `)
+		case r == '\r':
+		case r < 0x20:
+			fmt.Fprintf(&b, `\'%02x`, r)
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff: // the same in Windows-1252
+			fmt.Fprintf(&b, `\'%02x`, r)
+		default:
+			for _, u := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&b, `\u%d?`, int16(u))
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package rtf2txt
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	var b bytes.Buffer
+	err := Write(&b, []Block{
+		&Paragraph{Runs: []Run{{Text: `Hello {world} \ café`, Style: Style{Bold: true}}, {Text: " €", Style: Style{Font: "Arial", Size: 10}}}},
+		&Paragraph{Align: AlignCenter, Runs: []Run{{Text: "site", Style: Style{Link: "https://example.com/"}}}},
+		&Table{Rows: [][]Cell{{{Runs: []Run{{Text: "a"}}}, {Runs: []Run{{Text: "b"}}, Width: 1440}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{\rtf1\ansi\ansicpg1252\deff0\uc1
+{\fonttbl{\f0\fnil Times New Roman;}{\f1\fnil Arial;}}
+{\colortbl;\red0\green0\blue255;}
+\pard\plain\ql{\b\f0\fs24 Hello \{world\} \\ caf\'e9}{\f1\fs20  \u8364?}\par
+\pard\plain\qc{\field{\*\fldinst{HYPERLINK "https://example.com/"}}{\fldrslt{\ul\cf1\f0\fs24 site}}}\par
+\trowd\trgaph108\cellx2880\cellx4320
+\pard\plain\intbl{\f0\fs24 a}\cell
+\pard\plain\intbl{\f0\fs24 b}\cell
+\row
+}
+`
+	if b.String() != expected {
+		t.Error("expected", expected, "got", b.String())
+	}
+
+	text, err := Text(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := text.String(); !strings.HasPrefix(s, `Hello {world} \ café`) || !strings.Contains(s, "site a b") {
+		t.Errorf("expected written text to be read back, got %q", s)
+	}
+}
+
+func TestWriteRuns(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteRuns(&b, []Run{
+		{Text: "one\ntwo", Style: Style{Color: color.RGBA{0xff, 0, 0, 0xff}}},
+		{Text: " three\tfour", Style: Style{Italic: true, Color: color.RGBA{0xff, 0, 0, 0xff}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := b.String()
+	if strings.Count(s, "\\par\n") != 2 || strings.Count(s, `\red255\green0\blue0;`) != 1 {
+		t.Error("expected two paragraphs and one color", s)
+	}
+	if !strings.Contains(s, `{\i\cf1\f0\fs24  three\tab four}`) {
+		t.Error("expected italic run with a tab", s)
+	}
+
+	text, err := TextWithOptions(&b, Options{ParagraphBreak: "\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text.String() != "one\ntwo three four\n" {
+		t.Errorf("expected paragraphs to be read back, got %q", text.String())
+	}
+}
+
+func TestWriteAlignment(t *testing.T) {
+	var b bytes.Buffer
+	err := Write(&b, []Block{&Paragraph{Align: AlignRight}, &Paragraph{Align: Alignment(7)}, &Paragraph{Align: -1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := b.String(); strings.Count(s, `\pard\plain\qr`) != 1 || strings.Count(s, `\pard\plain\ql`) != 2 {
+		t.Error("expected unknown alignments to be left aligned", s)
+	}
+}
+
+func TestEscapeRTF(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{`a\b{c}`, `a\\b\{c\}`},
+		{"line\nbreak\r", `line\line break`},
+		{"ñ", `\'f1`},
+		{"Ω", `\u937?`},
+		{"€", `\u8364?`},
+		{"\U0001F600", `\u-10179?\u-8704?`},
+	}
+	for _, test := range tests {
+		if out := escapeRTF(test.in); out != test.out {
+			t.Errorf("expected %q to be escaped as %q, got %q", test.in, test.out, out)
+		}
+	}
+}